@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/aws/aws-k8s-tester/internal/deployers/eksapi"
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	var resourceID string
+	flag.StringVar(&resourceID, "resource-id", "", "resource ID of the kubetest2-eksapi run, e.g. kubetest2-eksapi-<run ID>")
+	var clusterName string
+	flag.StringVar(&clusterName, "cluster-name", "", "name of the cluster, if it differs from the resource ID")
+	var kubeconfigPath string
+	flag.StringVar(&kubeconfigPath, "kubeconfig", "", "kubeconfig of the cluster, to include Kubernetes objects and add-on logs")
+	var eksEndpointURL string
+	flag.StringVar(&eksEndpointURL, "endpoint-url", "", "Endpoint URL for the EKS API")
+	var logBucket string
+	flag.StringVar(&logBucket, "log-bucket", "", "S3 bucket to upload the support bundle to")
+	var outputDir string
+	flag.StringVar(&outputDir, "output-dir", ".", "directory to write the support bundle to")
+	flag.Parse()
+	if resourceID == "" {
+		klog.Fatalf("--resource-id is required")
+	}
+	c := eksapi.NewSupportBundleCollector(resourceID, clusterName, kubeconfigPath, eksEndpointURL, logBucket)
+	if err := c.Collect(context.Background(), outputDir); err != nil {
+		klog.Fatalf("failed to collect support bundle: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package eksapi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	addonOverrideEnvPrefix = "AWS_K8S_TESTER_ADDON_"
+	addonOverrideEnvSuffix = "_ENABLE"
+)
+
+// applyAddonOverrides toggles addons with AWS_K8S_TESTER_ADDON_<NAME>_ENABLE=true/false environment variables,
+// where <NAME> is the addon name upper-cased with dashes replaced by underscores (e.g. AWS_K8S_TESTER_ADDON_VPC_CNI_ENABLE).
+// A disabled addon is removed from the list regardless of version; an enabled addon that isn't already listed is added at its default version.
+func applyAddonOverrides(addons []string, environ []string) ([]string, error) {
+	var overrides []string
+	for _, env := range environ {
+		if strings.HasPrefix(env, addonOverrideEnvPrefix) {
+			overrides = append(overrides, env)
+		}
+	}
+	slices.Sort(overrides)
+	for _, env := range overrides {
+		key, value, _ := strings.Cut(env, "=")
+		if !strings.HasSuffix(key, addonOverrideEnvSuffix) {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, addonOverrideEnvPrefix), addonOverrideEnvSuffix)
+		if name == "" {
+			continue
+		}
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", key, err)
+		}
+		listed := slices.ContainsFunc(addons, func(addon string) bool {
+			return addonName(addon) == name
+		})
+		if enabled {
+			if !listed {
+				klog.Infof("enabling addon %s at its default version because %s=%s", name, key, value)
+				addons = append(addons, name+":default")
+			}
+		} else if listed {
+			klog.Infof("disabling addon %s because %s=%s", name, key, value)
+			addons = slices.DeleteFunc(addons, func(addon string) bool {
+				return addonName(addon) == name
+			})
+		}
+	}
+	return addons, nil
+}
+
+func addonName(addon string) string {
+	name, _, _ := strings.Cut(addon, ":")
+	return name
+}
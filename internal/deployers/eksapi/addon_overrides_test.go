@@ -0,0 +1,58 @@
+package eksapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_applyAddonOverrides(t *testing.T) {
+	cases := []struct {
+		name    string
+		addons  []string
+		environ []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "no overrides",
+			addons:  []string{"vpc-cni:latest"},
+			environ: []string{"HOME=/root"},
+			want:    []string{"vpc-cni:latest"},
+		},
+		{
+			name:    "disable listed addon",
+			addons:  []string{"vpc-cni:latest", "coredns:default", "vpc-cni:default"},
+			environ: []string{"AWS_K8S_TESTER_ADDON_VPC_CNI_ENABLE=false"},
+			want:    []string{"coredns:default"},
+		},
+		{
+			name:    "enable unlisted addon",
+			addons:  []string{"coredns:default"},
+			environ: []string{"AWS_K8S_TESTER_ADDON_EKS_POD_IDENTITY_AGENT_ENABLE=true"},
+			want:    []string{"coredns:default", "eks-pod-identity-agent:default"},
+		},
+		{
+			name:    "enable listed addon keeps version",
+			addons:  []string{"kube-proxy:latest"},
+			environ: []string{"AWS_K8S_TESTER_ADDON_KUBE_PROXY_ENABLE=true"},
+			want:    []string{"kube-proxy:latest"},
+		},
+		{
+			name:    "invalid value",
+			environ: []string{"AWS_K8S_TESTER_ADDON_COREDNS_ENABLE=maybe"},
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := applyAddonOverrides(c.addons, c.environ)
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
@@ -2,17 +2,20 @@ package eksapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"k8s.io/klog/v2"
 )
 
 const (
 	addonCreationTimeout = 5 * time.Minute
+	addonDeletionTimeout = 5 * time.Minute
 )
 
 type AddonManager struct {
@@ -25,9 +28,7 @@ func NewAddonManager(clients *awsClients) *AddonManager {
 	}
 }
 
-func (m *AddonManager) createAddons(infra *Infrastructure, cluster *Cluster, opts *deployerOptions) error {
-	ctx := context.TODO()
-
+func (m *AddonManager) createAddons(ctx context.Context, infra *Infrastructure, cluster *Cluster, opts *deployerOptions) error {
 	addonMap := map[string]string{}
 	for _, addon := range opts.Addons {
 		addonParts := strings.Split(addon, ":")
@@ -37,7 +38,7 @@ func (m *AddonManager) createAddons(infra *Infrastructure, cluster *Cluster, opt
 		name := addonParts[0]
 		version := addonParts[1]
 		klog.Infof("resolving addon %s version: %s", name, version)
-		resolvedVersion, err := m.resolveAddonVersion(name, version, opts.KubernetesVersion)
+		resolvedVersion, err := m.resolveAddonVersion(ctx, name, version, opts.KubernetesVersion)
 		if err != nil {
 			return err
 		}
@@ -46,6 +47,7 @@ func (m *AddonManager) createAddons(infra *Infrastructure, cluster *Cluster, opt
 	}
 
 	for addonName, addonVersion := range addonMap {
+		sleepWithJitter(opts.MaxJitter, fmt.Sprintf("creating addon %s", addonName))
 		klog.Infof("creating addon %s version: %s", addonName, addonVersion)
 		input := eks.CreateAddonInput{
 			AddonName:    aws.String(addonName),
@@ -70,12 +72,55 @@ func (m *AddonManager) createAddons(infra *Infrastructure, cluster *Cluster, opt
 	return nil
 }
 
-func (m *AddonManager) resolveAddonVersion(name string, versionMarker string, kubernetesVersion string) (string, error) {
+// deleteAddons deletes the cluster's managed addons before the cluster itself,
+// so a stuck addon surfaces as an addon failure rather than a cluster deletion timeout
+func (m *AddonManager) deleteAddons(ctx context.Context, clusterName string) error {
+	var addonNames []string
+	paginator := eks.NewListAddonsPaginator(m.clients.EKS(), &eks.ListAddonsInput{
+		ClusterName: aws.String(clusterName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			var notFound *ekstypes.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				klog.Infof("cluster does not exist, no addons to delete: %s", clusterName)
+				return nil
+			}
+			return fmt.Errorf("failed to list addons: %v", err)
+		}
+		addonNames = append(addonNames, page.Addons...)
+	}
+	for _, addonName := range addonNames {
+		klog.Infof("deleting addon: %s", addonName)
+		_, err := m.clients.EKS().DeleteAddon(ctx, &eks.DeleteAddonInput{
+			AddonName:   aws.String(addonName),
+			ClusterName: aws.String(clusterName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete addon %s: %v", addonName, err)
+		}
+	}
+	for _, addonName := range addonNames {
+		klog.Infof("waiting for addon to be deleted: %s", addonName)
+		err := eks.NewAddonDeletedWaiter(m.clients.EKS()).
+			Wait(ctx, &eks.DescribeAddonInput{
+				AddonName:   aws.String(addonName),
+				ClusterName: aws.String(clusterName),
+			}, addonDeletionTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to wait for addon %s to be deleted: %v", addonName, err)
+		}
+	}
+	return nil
+}
+
+func (m *AddonManager) resolveAddonVersion(ctx context.Context, name string, versionMarker string, kubernetesVersion string) (string, error) {
 	input := eks.DescribeAddonVersionsInput{
 		AddonName:         aws.String(name),
 		KubernetesVersion: aws.String(kubernetesVersion),
 	}
-	descOutput, err := m.clients.EKS().DescribeAddonVersions(context.TODO(), &input)
+	descOutput, err := m.clients.EKS().DescribeAddonVersions(ctx, &input)
 	if err != nil {
 		return "", err
 	}
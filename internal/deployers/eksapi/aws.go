@@ -4,6 +4,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
@@ -20,6 +21,7 @@ type awsClients struct {
 	_iam       *iam.Client
 	_s3        *s3.Client
 	_s3Presign *s3.PresignClient
+	_cwl       *cloudwatchlogs.Client
 }
 
 func newAWSClients(config aws.Config, eksEndpointURL string) *awsClients {
@@ -30,6 +32,7 @@ func newAWSClients(config aws.Config, eksEndpointURL string) *awsClients {
 		_ssm: ssm.NewFromConfig(config),
 		_iam: iam.NewFromConfig(config),
 		_s3:  s3.NewFromConfig(config),
+		_cwl: cloudwatchlogs.NewFromConfig(config),
 	}
 	clients._s3Presign = s3.NewPresignClient(clients._s3)
 	if eksEndpointURL != "" {
@@ -73,3 +76,7 @@ func (c *awsClients) S3() *s3.Client {
 func (c *awsClients) S3Presign() *s3.PresignClient {
 	return c._s3Presign
 }
+
+func (c *awsClients) CloudWatchLogs() *cloudwatchlogs.Client {
+	return c._cwl
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/aws/aws-k8s-tester/internal/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
 	"k8s.io/klog/v2"
 )
 
@@ -32,9 +34,11 @@ type Cluster struct {
 	arn                      string
 	name                     string
 	cidr                     string
+	// oidcIssuer is the URL of the cluster's OpenID Connect issuer, used for IAM roles for service accounts
+	oidcIssuer string
 }
 
-func (m *ClusterManager) getOrCreateCluster(infra *Infrastructure, opts *deployerOptions) (*Cluster, error) {
+func (m *ClusterManager) getOrCreateCluster(ctx context.Context, infra *Infrastructure, opts *deployerOptions) (*Cluster, error) {
 	targetClusterName := opts.StaticClusterName
 	if targetClusterName == "" {
 		klog.Infof("creating cluster...")
@@ -77,7 +81,7 @@ func (m *ClusterManager) getOrCreateCluster(infra *Infrastructure, opts *deploye
 		if err != nil {
 			return nil, fmt.Errorf("failed to create API options: %v", err)
 		}
-		createOutput, err := m.clients.EKS().CreateCluster(context.TODO(), &input,
+		createOutput, err := m.clients.EKS().CreateCluster(ctx, &input,
 			func(o *eks.Options) {
 				o.APIOptions = apiOpts
 			})
@@ -88,16 +92,16 @@ func (m *ClusterManager) getOrCreateCluster(infra *Infrastructure, opts *deploye
 	} else {
 		klog.Infof("reusing existing static cluster %s", opts.StaticClusterName)
 	}
-	cluster, waitErr := m.waitForClusterActive(targetClusterName, opts.ClusterCreationTimeout)
+	cluster, waitErr := m.waitForClusterActive(ctx, targetClusterName, opts.ClusterCreationTimeout)
 	if waitErr != nil {
 		return nil, fmt.Errorf("failed to wait for cluster to become active: %v", waitErr)
 	}
 	return cluster, nil
 }
 
-func (m *ClusterManager) waitForClusterActive(clusterName string, timeout time.Duration) (*Cluster, error) {
+func (m *ClusterManager) waitForClusterActive(ctx context.Context, clusterName string, timeout time.Duration) (*Cluster, error) {
 	klog.Infof("waiting for cluster to be active: %s", clusterName)
-	out, err := eks.NewClusterActiveWaiter(m.clients.EKS()).WaitForOutput(context.TODO(), &eks.DescribeClusterInput{
+	out, err := eks.NewClusterActiveWaiter(m.clients.EKS()).WaitForOutput(ctx, &eks.DescribeClusterInput{
 		Name: aws.String(clusterName),
 	}, timeout)
 	// log when possible, whether there was an error or not
@@ -117,6 +121,11 @@ func (m *ClusterManager) waitForClusterActive(clusterName string, timeout time.D
 	default:
 		return nil, fmt.Errorf("unknown cluster IP family: '%v'", out.Cluster.KubernetesNetworkConfig.IpFamily)
 	}
+	var oidcIssuer string
+	if out.Cluster.Identity != nil && out.Cluster.Identity.Oidc != nil {
+		oidcIssuer = aws.ToString(out.Cluster.Identity.Oidc.Issuer)
+		klog.Infof("cluster OIDC issuer: %s", oidcIssuer)
+	}
 	return &Cluster{
 		arn:                      *out.Cluster.Arn,
 		certificateAuthorityData: *out.Cluster.CertificateAuthority.Data,
@@ -124,6 +133,7 @@ func (m *ClusterManager) waitForClusterActive(clusterName string, timeout time.D
 		endpoint:                 *out.Cluster.Endpoint,
 		name:                     *out.Cluster.Name,
 		securityGroupId:          *out.Cluster.ResourcesVpcConfig.ClusterSecurityGroupId,
+		oidcIssuer:               oidcIssuer,
 	}, nil
 }
 
@@ -144,44 +154,70 @@ func (m *ClusterManager) isClusterActive() (bool, error) {
 	}
 }
 
-func (m *ClusterManager) deleteCluster() error {
-    const (
-        retryInterval = 2 * time.Minute
-        maxAttempts   = 5
-    )
-
-    for attempt := 1; attempt <= maxAttempts; attempt++ {
-        input := eks.DeleteClusterInput{
-            Name: aws.String(m.resourceID),
-        }
-
-        klog.Infof("Attempt %d: deleting cluster...", attempt)
-        out, err := m.clients.EKS().DeleteCluster(context.TODO(), &input)
-        if err != nil {
-            var notFound *ekstypes.ResourceNotFoundException
-            if errors.As(err, &notFound) {
-                klog.Infof("cluster does not exist: %s", m.resourceID)
-                return nil
-            }
-            if attempt == maxAttempts {
-                return fmt.Errorf("Failed to delete cluster after %d attempts: %v", maxAttempts, err)
-            }
-            klog.Infof("Deletion failed: %v. Waiting %v before retry...", err, retryInterval)
-            time.Sleep(retryInterval)
-            continue
-        }
-
-        klog.Infof("waiting for cluster to be deleted: %s", *out.Cluster.Arn)
-        err = eks.NewClusterDeletedWaiter(m.clients.EKS()).
-            Wait(context.TODO(), &eks.DescribeClusterInput{
-                Name: aws.String(m.resourceID),
-            }, time.Minute*15)
+const (
+	clusterDeleteRetryInterval = 15 * time.Second
+	clusterDeleteRetryTimeout  = 10 * time.Minute
+	clusterDeletionTimeout     = 15 * time.Minute
+)
 
-        if err != nil {
-            return fmt.Errorf("failed to wait for cluster to be deleted: %v", err)
-        }
-        return nil
-    }
+// isRetryableClusterDeleteError reports whether DeleteCluster may succeed if retried,
+// i.e. the cluster is still in use by resources being torn down, or the call was throttled
+func isRetryableClusterDeleteError(err error) bool {
+	var inUse *ekstypes.ResourceInUseException
+	if errors.As(err, &inUse) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, throttled := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]
+	return throttled
+}
 
-    return fmt.Errorf("Failed to delete cluster after %d attempts", maxAttempts)
-}
\ No newline at end of file
+func (m *ClusterManager) deleteCluster(ctx context.Context) error {
+	var clusterARN string
+	var lastErr error
+	attempt := 0
+	// DeleteCluster fails while resources like nodegroups are still being torn down, so retry it with backoff
+	err := util.PollUntil(ctx, clusterDeleteRetryInterval, clusterDeleteRetryTimeout, func(ctx context.Context) (bool, error) {
+		attempt++
+		klog.Infof("Attempt %d: deleting cluster...", attempt)
+		out, err := m.clients.EKS().DeleteCluster(ctx, &eks.DeleteClusterInput{
+			Name: aws.String(m.resourceID),
+		})
+		if err != nil {
+			var notFound *ekstypes.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				return true, nil
+			}
+			if !isRetryableClusterDeleteError(err) {
+				return false, err
+			}
+			klog.Infof("Deletion failed: %v", err)
+			lastErr = err
+			return false, nil
+		}
+		clusterARN = aws.ToString(out.Cluster.Arn)
+		return true, nil
+	})
+	if err != nil {
+		if lastErr != nil {
+			return fmt.Errorf("failed to delete cluster after %d attempts: %v (last error: %v)", attempt, err, lastErr)
+		}
+		return fmt.Errorf("failed to delete cluster after %d attempts: %v", attempt, err)
+	}
+	if clusterARN == "" {
+		klog.Infof("cluster does not exist: %s", m.resourceID)
+		return nil
+	}
+	klog.Infof("waiting for cluster to be deleted: %s", clusterARN)
+	err = eks.NewClusterDeletedWaiter(m.clients.EKS()).
+		Wait(ctx, &eks.DescribeClusterInput{
+			Name: aws.String(m.resourceID),
+		}, clusterDeletionTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to wait for cluster to be deleted: %v", err)
+	}
+	return nil
+}
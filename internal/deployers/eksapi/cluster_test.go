@@ -0,0 +1,19 @@
+package eksapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isRetryableClusterDeleteError(t *testing.T) {
+	assert.True(t, isRetryableClusterDeleteError(&ekstypes.ResourceInUseException{Message: aws.String("nodegroups attached")}))
+	assert.True(t, isRetryableClusterDeleteError(&smithy.GenericAPIError{Code: "ThrottlingException"}))
+	assert.False(t, isRetryableClusterDeleteError(&smithy.GenericAPIError{Code: "AccessDeniedException"}))
+	assert.False(t, isRetryableClusterDeleteError(&ekstypes.InvalidParameterException{Message: aws.String("invalid name")}))
+	assert.False(t, isRetryableClusterDeleteError(errors.New("connection reset")))
+}
@@ -1,9 +1,13 @@
 package eksapi
 
 import (
+	"math/rand/v2"
 	"os"
 	"slices"
 	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
 )
 
 const AvailabilityZonePriorityEnv = "EKSAPI_AZ_PRIORITY"
@@ -26,3 +30,14 @@ func availabilityZoneHintedOrder(availabilityZones []string) []string {
 		return 0
 	})
 }
+
+// sleepWithJitter sleeps for a random duration up to maxJitter,
+// so that many deployers started at once spread out their AWS API calls instead of being throttled together
+func sleepWithJitter(maxJitter time.Duration, reason string) {
+	if maxJitter <= 0 {
+		return
+	}
+	jitter := rand.N(maxJitter)
+	klog.Infof("sleeping for %v before %s", jitter, reason)
+	time.Sleep(jitter)
+}
@@ -0,0 +1,71 @@
+package eksapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// defaultControlPlaneMetricsPaths are scraped when --dump-control-plane-metrics is set without --control-plane-metrics-paths.
+// On EKS, the API server's /metrics includes the etcd client histograms (etcd_request_duration_seconds, etc.),
+// and the scheduler and controller-manager are exposed through the metrics.eks.amazonaws.com API.
+var defaultControlPlaneMetricsPaths = []string{
+	"/metrics",
+}
+
+const controlPlaneMetricsDir = "control-plane-metrics"
+
+// dumpControlPlaneMetrics scrapes each path from the API server and writes the raw response to outputDir.
+// Failures are collected per path, so one unreachable path doesn't prevent the others from being saved.
+func (k *k8sClient) dumpControlPlaneMetrics(paths []string, bearerTokenFile string, outputDir string) error {
+	clientset := k.clientset
+	if bearerTokenFile != "" {
+		config := rest.CopyConfig(k.config)
+		// the token file replaces the kubeconfig's credentials entirely
+		config.ExecProvider = nil
+		config.AuthProvider = nil
+		config.BearerToken = ""
+		config.BearerTokenFile = bearerTokenFile
+		c, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return fmt.Errorf("failed to create client for control plane metrics: %v", err)
+		}
+		clientset = c
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create control plane metrics directory: %v", err)
+	}
+	var errs []error
+	for _, path := range paths {
+		klog.Infof("scraping control plane metrics: %s", path)
+		body, err := clientset.CoreV1().RESTClient().Get().AbsPath(path).DoRaw(context.TODO())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to scrape %s: %v", path, err))
+			continue
+		}
+		outputPath := filepath.Join(outputDir, controlPlaneMetricsFileName(path))
+		if err := os.WriteFile(outputPath, body, 0644); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write %s: %v", outputPath, err))
+			continue
+		}
+		klog.Infof("wrote control plane metrics snapshot: %s", outputPath)
+	}
+	return errors.Join(errs...)
+}
+
+// controlPlaneMetricsFileName turns an API path like /apis/metrics.eks.amazonaws.com/v1/ksh/container/metrics
+// into a flat file name
+func controlPlaneMetricsFileName(path string) string {
+	name := strings.Trim(path, "/")
+	if name == "" {
+		name = "root"
+	}
+	return strings.ReplaceAll(name, "/", "_") + ".txt"
+}
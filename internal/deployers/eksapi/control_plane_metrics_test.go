@@ -0,0 +1,13 @@
+package eksapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_controlPlaneMetricsFileName(t *testing.T) {
+	assert.Equal(t, "metrics.txt", controlPlaneMetricsFileName("/metrics"))
+	assert.Equal(t, "apis_metrics.eks.amazonaws.com_v1_ksh_container_metrics.txt", controlPlaneMetricsFileName("/apis/metrics.eks.amazonaws.com/v1/ksh/container/metrics"))
+	assert.Equal(t, "root.txt", controlPlaneMetricsFileName("/"))
+}
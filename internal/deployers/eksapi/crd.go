@@ -0,0 +1,99 @@
+package eksapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const crdEstablishedTimeout = 2 * time.Minute
+
+var crdResource = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// addonCRDs are the CRDs that managed addons install, which must be established before custom resources of their kinds can be created.
+// An addon that becomes active can still have CRDs that aren't served yet, and creating a CR then fails with "no matches for kind".
+var addonCRDs = map[string][]string{
+	"snapshot-controller": {
+		"volumesnapshotclasses.snapshot.storage.k8s.io",
+		"volumesnapshotcontents.snapshot.storage.k8s.io",
+		"volumesnapshots.snapshot.storage.k8s.io",
+	},
+}
+
+// autoModeCRDs are the CRDs that EKS Auto Mode installs, which the deployer creates a NodeClass and NodePool from
+var autoModeCRDs = []string{
+	"nodeclasses.eks.amazonaws.com",
+	"nodepools.karpenter.sh",
+}
+
+// requiredCRDs returns the CRDs installed by the addons, which are name:version pairs
+func requiredCRDs(addons []string) []string {
+	var crds []string
+	for _, addon := range addons {
+		for _, crd := range addonCRDs[addonName(addon)] {
+			if !slices.Contains(crds, crd) {
+				crds = append(crds, crd)
+			}
+		}
+	}
+	slices.Sort(crds)
+	return crds
+}
+
+// waitForCRDsEstablished waits until each of the CRDs exists and has the Established condition
+func (k *k8sClient) waitForCRDsEstablished(ctx context.Context, crds []string, timeout time.Duration) error {
+	if len(crds) == 0 {
+		return nil
+	}
+	klog.Infof("waiting up to %v for CRDs to be established: %v", timeout, crds)
+	pending := crds
+	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		var stillPending []string
+		for _, name := range pending {
+			crd, err := k.dclient.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					klog.Warningf("failed to get CRD %s: %v", name, err)
+				}
+				stillPending = append(stillPending, name)
+				continue
+			}
+			if !isCRDEstablished(crd) {
+				stillPending = append(stillPending, name)
+			}
+		}
+		pending = stillPending
+		return len(pending) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("CRDs were not established: %v: %v", pending, err)
+	}
+	klog.Infof("CRDs are established: %v", crds)
+	return nil
+}
+
+func isCRDEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if c["type"] == "Established" && c["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package eksapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_requiredCRDs(t *testing.T) {
+	assert.Empty(t, requiredCRDs([]string{"vpc-cni:latest", "coredns:default"}))
+	assert.Equal(t, []string{
+		"volumesnapshotclasses.snapshot.storage.k8s.io",
+		"volumesnapshotcontents.snapshot.storage.k8s.io",
+		"volumesnapshots.snapshot.storage.k8s.io",
+	}, requiredCRDs([]string{"vpc-cni:latest", "snapshot-controller:default", "snapshot-controller:latest"}))
+}
+
+func Test_isCRDEstablished(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+				map[string]interface{}{"type": "Established", "status": "False"},
+			},
+		},
+	}}
+	assert.False(t, isCRDEstablished(crd))
+
+	crd.Object["status"].(map[string]interface{})["conditions"].([]interface{})[1].(map[string]interface{})["status"] = "True"
+	assert.True(t, isCRDEstablished(crd))
+
+	assert.False(t, isCRDEstablished(&unstructured.Unstructured{Object: map[string]interface{}{}}))
+}
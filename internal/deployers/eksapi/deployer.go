@@ -4,6 +4,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/url"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 	"github.com/urfave/sflags/gen/gpflag"
 	"golang.org/x/exp/slices"
 	"k8s.io/klog"
+	"sigs.k8s.io/kubetest2/pkg/artifacts"
 	"sigs.k8s.io/kubetest2/pkg/types"
 )
 
@@ -58,41 +61,59 @@ type deployer struct {
 }
 
 type deployerOptions struct {
-	Addons                      []string      `flag:"addons" desc:"Managed addons (name:version pairs) to create in the cluster. Use 'latest' for the most recent version, or 'default' for the default version."`
-	AMI                         string        `flag:"ami" desc:"AMI for unmanaged nodes"`
-	AMIType                     string        `flag:"ami-type" desc:"AMI type for managed nodes"`
-	AutoMode                    bool          `flag:"auto-mode" desc:"Enable EKS Auto Mode"`
-	CapacityReservation         bool          `flag:"capacity-reservation" desc:"Use capacity reservation for the unmanaged nodegroup"`
-	TargetCapacityReservationId string        `flag:"target-capacity-reservation-id" desc:"CapacityReservation ID to use for targeted launches. Implies --capacity-reservation."`
-	ClusterCreationTimeout      time.Duration `flag:"cluster-creation-timeout" desc:"Time to wait for cluster to be created and become active."`
-	ClusterRoleServicePrincipal string        `flag:"cluster-role-service-principal" desc:"Additional service principal that can assume the cluster role"`
-	DeployCloudwatchInfra       bool          `flag:"deploy-cloudwatch-infra" desc:"Deploy required infrastructure for emitting metrics to CloudWatch"`
-	EFA                         bool          `flag:"efa" desc:"Create EFA interfaces on the node of an unmanaged nodegroup. One instance type must be passed if set. Requires --unmanaged-nodes and --instance-types."`
-	EKSEndpointURL              string        `flag:"endpoint-url" desc:"Endpoint URL for the EKS API"`
-	EmitMetrics                 bool          `flag:"emit-metrics" desc:"Record and emit metrics to CloudWatch"`
-	ExpectedAMI                 string        `flag:"expected-ami" desc:"Expected AMI of nodes. Up will fail if the actual nodes are not utilizing the expected AMI. Defaults to --ami if defined."`
+	Addons                       []string      `flag:"addons" desc:"Managed addons (name:version pairs) to create in the cluster. Use 'latest' for the most recent version, or 'default' for the default version."`
+	AMI                          string        `flag:"ami" desc:"AMI for unmanaged nodes"`
+	AMIType                      string        `flag:"ami-type" desc:"AMI type for managed nodes"`
+	AutoMode                     bool          `flag:"auto-mode" desc:"Enable EKS Auto Mode"`
+	CapacityReservation          bool          `flag:"capacity-reservation" desc:"Use capacity reservation for the unmanaged nodegroup"`
+	ClusterCreationTimeout       time.Duration `flag:"cluster-creation-timeout" desc:"Time to wait for cluster to be created and become active."`
+	ClusterReadyWebhookTimeout   time.Duration `flag:"cluster-ready-webhook-timeout" desc:"Time limit for the --cluster-ready-webhook-url request. Defaults to 10s"`
+	ClusterReadyWebhookURL       string        `flag:"cluster-ready-webhook-url" desc:"URL to POST the cluster's name, ARN, and endpoint to as JSON once its API server is reachable, before addons and nodes are created. Failures are logged, not fatal"`
+	ClusterRoleServicePrincipal  string        `flag:"cluster-role-service-principal" desc:"Additional service principal that can assume the cluster role"`
+	ControlPlaneMetricsPaths     []string      `flag:"control-plane-metrics-paths" desc:"API server paths to scrape when --dump-control-plane-metrics is set. Defaults to /metrics"`
+	ControlPlaneMetricsTokenFile string        `flag:"control-plane-metrics-token-file" desc:"File containing a bearer token used to scrape control plane metrics, instead of the kubeconfig credentials"`
+	DeleteLogGroups              bool          `flag:"delete-log-groups" desc:"In Down, delete the cluster's CloudWatch log groups (/aws/eks/<cluster>/ and /aws/containerinsights/<cluster>/, with the cluster's tags), which otherwise outlive the cluster"`
+	DeleteOnFailure              bool          `flag:"delete-on-failure" desc:"Delete the resources created so far if Up fails, including when --up-timeout elapses. The deletion is bounded by --down-timeout"`
+	DeployCloudwatchInfra        bool          `flag:"deploy-cloudwatch-infra" desc:"Deploy required infrastructure for emitting metrics to CloudWatch"`
+	DownTimeout                  time.Duration `flag:"down-timeout" desc:"Overall time limit for Down. Once it elapses, the deletion step in progress is aborted and the error names its phase"`
+	DumpControlPlaneMetrics      bool          `flag:"dump-control-plane-metrics" desc:"Save a snapshot of the control plane's Prometheus metrics to the artifacts directory when dumping cluster logs"`
+	DumpSupportBundle            bool          `flag:"dump-support-bundle" desc:"Gather the cluster and add-on descriptions, CloudFormation stack events, Kubernetes nodes, pods, and events, and add-on logs into a tarball when dumping cluster logs. Uploaded to --log-bucket if set"`
+	EFA                          bool          `flag:"efa" desc:"Create EFA interfaces on the node of an unmanaged nodegroup. One instance type must be passed if set. Requires --unmanaged-nodes and --instance-types."`
+	EKSEndpointURL               string        `flag:"endpoint-url" desc:"Endpoint URL for the EKS API"`
+	EmitMetrics                  bool          `flag:"emit-metrics" desc:"Record and emit metrics to CloudWatch"`
+	ExpectedAMI                  string        `flag:"expected-ami" desc:"Expected AMI of nodes. Up will fail if the actual nodes are not utilizing the expected AMI. Defaults to --ami if defined."`
 	// TODO: remove this once it's no longer used in downstream jobs
-	GenerateSSHKey          bool          `flag:"generate-ssh-key" desc:"Generate an SSH key to use for tests. The generated key should not be used in production, as it will not have a passphrase."`
-	InstanceTypes           []string      `flag:"instance-types" desc:"Node instance types. Cannot be used with --instance-type-archs"`
-	InstanceTypeArchs       []string      `flag:"instance-type-archs" desc:"Use default node instance types for specific architectures. Cannot be used with --instance-types"`
-	IPFamily                string        `flag:"ip-family" desc:"IP family for the cluster (ipv4 or ipv6)"`
-	KubeconfigPath          string        `flag:"kubeconfig" desc:"Path to kubeconfig"`
-	KubernetesVersion       string        `flag:"kubernetes-version" desc:"cluster Kubernetes version"`
-	LogBucket               string        `flag:"log-bucket" desc:"S3 bucket for storing logs for each run. If empty, logs will not be stored."`
-	NodeadmFeatureGates     []string      `flag:"nodeadm-feature-gates" desc:"Feature gates to enable for nodeadm (key=value pairs)"`
-	NodeCreationTimeout     time.Duration `flag:"node-creation-timeout" desc:"Time to wait for nodes to be created/launched. This should consider instance availability."`
-	NodeReadyTimeout        time.Duration `flag:"node-ready-timeout" desc:"Time to wait for all nodes to become ready"`
-	Nodes                   int           `flag:"nodes" desc:"number of nodes to launch in cluster"`
-	NodeNameStrategy        string        `flag:"node-name-strategy" desc:"Specifies the naming strategy for node. Allowed values: ['SessionName', 'EC2PrivateDNSName'], default to EC2PrivateDNSName"`
-	Region                  string        `flag:"region" desc:"AWS region for EKS cluster"`
-	SkipNodeReadinessChecks bool          `flag:"skip-node-readiness-checks" desc:"Skip performing readiness checks on created nodes"`
-	StaticClusterName       string        `flag:"static-cluster-name" desc:"Optional when re-use existing cluster and node group by querying the kubeconfig and run test"`
-	SetClusterDNSIP         bool          `flag:"set-cluster-dns-ip" desc:"Explicitly set cluster-dns-ip in node userdata instead of letting the node derive it"`
-	TuneVPCCNI              bool          `flag:"tune-vpc-cni" desc:"Apply tuning parameters to the VPC CNI DaemonSet"`
-	UnmanagedNodes          bool          `flag:"unmanaged-nodes" desc:"Use an AutoScalingGroup instead of an EKS-managed nodegroup. Requires --ami"`
-	UpClusterHeaders        []string      `flag:"up-cluster-header" desc:"Additional header to add to eks:CreateCluster requests. Specified in the same format as curl's -H flag."`
-	UserDataFormat          string        `flag:"user-data-format" desc:"Format of the node instance user data"`
-	ZoneType                string        `flag:"zone-type" desc:"Type of zone to use for infrastructure (availability-zone, local-zone, etc). Defaults to availability-zone"`
+	GenerateSSHKey              bool          `flag:"generate-ssh-key" desc:"Generate an SSH key to use for tests. The generated key should not be used in production, as it will not have a passphrase."`
+	InstanceTypeArchs           []string      `flag:"instance-type-archs" desc:"Use default node instance types for specific architectures. Cannot be used with --instance-types"`
+	InstanceTypes               []string      `flag:"instance-types" desc:"Node instance types. Cannot be used with --instance-type-archs"`
+	IPFamily                    string        `flag:"ip-family" desc:"IP family for the cluster (ipv4 or ipv6)"`
+	KubeconfigPath              string        `flag:"kubeconfig" desc:"Path to kubeconfig"`
+	KubernetesVersion           string        `flag:"kubernetes-version" desc:"cluster Kubernetes version"`
+	LogBucket                   string        `flag:"log-bucket" desc:"S3 bucket for storing logs for each run. If empty, logs will not be stored."`
+	MaxJitter                   time.Duration `flag:"max-jitter" desc:"Maximum random delay before the first AWS API calls in Up, and between addon creations. Spreads out API calls when many clusters are created at once"`
+	NodeadmFeatureGates         []string      `flag:"nodeadm-feature-gates" desc:"Feature gates to enable for nodeadm (key=value pairs)"`
+	NodeCreationTimeout         time.Duration `flag:"node-creation-timeout" desc:"Time to wait for nodes to be created/launched. This should consider instance availability."`
+	NodeNameStrategy            string        `flag:"node-name-strategy" desc:"Specifies the naming strategy for node. Allowed values: ['SessionName', 'EC2PrivateDNSName'], default to EC2PrivateDNSName"`
+	NodeReadyPercent            int           `flag:"node-ready-threshold-percent" desc:"Percentage of --nodes that must become ready before proceeding. Defaults to 100"`
+	NodeReadyTimeout            time.Duration `flag:"node-ready-timeout" desc:"Time to wait for all nodes to become ready"`
+	Nodes                       int           `flag:"nodes" desc:"number of nodes to launch in cluster"`
+	PodAnnotations              []string      `flag:"pod-annotations" desc:"Annotations (key=value pairs) to add to every pod created by the deployer"`
+	PodLabels                   []string      `flag:"pod-labels" desc:"Labels (key=value pairs) to add to every pod created by the deployer"`
+	Region                      string        `flag:"region" desc:"AWS region for EKS cluster"`
+	RunDirRetentionMaxAge       time.Duration `flag:"rundir-retention-max-age" desc:"Delete previous runs' directories older than this at the start of Up. Disabled by default"`
+	RunDirRetentionMaxSize      string        `flag:"rundir-retention-max-size" desc:"Delete the oldest previous runs' directories at the start of Up until they total at most this size (e.g. 10Gi). Disabled by default"`
+	SetClusterDNSIP             bool          `flag:"set-cluster-dns-ip" desc:"Explicitly set cluster-dns-ip in node userdata instead of letting the node derive it"`
+	SkipNodeReadinessChecks     bool          `flag:"skip-node-readiness-checks" desc:"Skip performing readiness checks on created nodes"`
+	StaticClusterName           string        `flag:"static-cluster-name" desc:"Optional when re-use existing cluster and node group by querying the kubeconfig and run test"`
+	TargetCapacityReservationId string        `flag:"target-capacity-reservation-id" desc:"CapacityReservation ID to use for targeted launches. Implies --capacity-reservation."`
+	TuneVPCCNI                  bool          `flag:"tune-vpc-cni" desc:"Apply tuning parameters to the VPC CNI DaemonSet"`
+	UnmanagedNodes              bool          `flag:"unmanaged-nodes" desc:"Use an AutoScalingGroup instead of an EKS-managed nodegroup. Requires --ami"`
+	UpClusterHeaders            []string      `flag:"up-cluster-header" desc:"Additional header to add to eks:CreateCluster requests. Specified in the same format as curl's -H flag."`
+	UpTimeout                   time.Duration `flag:"up-timeout" desc:"Overall time limit for Up. Once it elapses, the step in progress is aborted and the error names its phase. See --delete-on-failure"`
+	UserDataFormat              string        `flag:"user-data-format" desc:"Format of the node instance user data"`
+	VerifyDNS                   bool          `flag:"verify-dns" desc:"After nodes are ready, run a pod that resolves in-cluster and external DNS names"`
+	VerifyDown                  bool          `flag:"verify-down" desc:"After Down, wait for the cluster, infrastructure stack, and node role to be gone, retrying the deletion once if any remain"`
+	ZoneType                    string        `flag:"zone-type" desc:"Type of zone to use for infrastructure (availability-zone, local-zone, etc). Defaults to availability-zone"`
 }
 
 // NewDeployer implements deployer.New for EKS using the EKS (and other AWS) API(s) directly (no cloudformation)
@@ -124,6 +145,8 @@ func (d *deployer) Version() string {
 func (d *deployer) Init() error {
 	d.initTime = time.Now()
 	awsConfig := awssdk.NewConfig()
+	mutationLog := newMutationLog(filepath.Join(artifacts.BaseDir(), mutationLogFileName))
+	awsConfig.APIOptions = append(awsConfig.APIOptions, mutationLog.addMiddleware)
 	d.awsClients = newAWSClients(awsConfig, d.EKSEndpointURL)
 	resourceID := ResourcePrefix + "-" + d.commonOptions.RunID()
 	if d.deployerOptions.EmitMetrics {
@@ -153,8 +176,33 @@ func (d *deployer) Build() error {
 	return nil
 }
 
-// DumpClusterLogs is a no-op
 func (d *deployer) DumpClusterLogs() error {
+	if d.DumpControlPlaneMetrics {
+		if d.k8sClient == nil {
+			klog.Infof("no k8s client available, control plane metrics will not be dumped!")
+		} else {
+			paths := d.ControlPlaneMetricsPaths
+			if len(paths) == 0 {
+				paths = defaultControlPlaneMetricsPaths
+			}
+			outputDir := filepath.Join(artifacts.BaseDir(), controlPlaneMetricsDir)
+			if err := d.k8sClient.dumpControlPlaneMetrics(paths, d.ControlPlaneMetricsTokenFile, outputDir); err != nil {
+				klog.Warningf("failed to dump control plane metrics: %v", err)
+				// don't return err, this isn't critical
+			}
+		}
+	}
+	if d.DumpSupportBundle {
+		var clusterName string
+		if d.cluster != nil {
+			clusterName = d.cluster.name
+		}
+		outputDir := filepath.Join(artifacts.BaseDir(), supportBundleDir)
+		if err := d.logManager.dumpSupportBundle(context.TODO(), d.k8sClient, &d.deployerOptions, clusterName, d.LogBucket, outputDir); err != nil {
+			klog.Warningf("failed to dump support bundle: %v", err)
+			// don't return err, this isn't critical
+		}
+	}
 	return nil
 }
 
@@ -172,17 +220,52 @@ func (d *deployer) Kubeconfig() (string, error) {
 }
 
 func (d *deployer) Up() error {
+	sleepWithJitter(d.MaxJitter, "calling AWS APIs")
 	if err := d.verifyUpFlags(); err != nil {
 		return fmt.Errorf("up flags are invalid: %v", err)
 	}
+	if err := d.pruneRunDirs(); err != nil {
+		return err
+	}
+	deadline, ctx, cancel := newPhaseDeadline(context.Background(), "Up", d.UpTimeout)
+	defer cancel()
+	if err := d.up(ctx, deadline); err != nil {
+		err = deadline.err(ctx, err)
+		if d.DeleteOnFailure && d.deployerOptions.StaticClusterName == "" {
+			return d.deleteOnFailure(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// deleteOnFailure deletes the resources a failed Up created, within --down-timeout, and returns upErr
+func (d *deployer) deleteOnFailure(upErr error) error {
+	klog.Warningf("Up failed, deleting the resources created so far: %v", upErr)
+	deadline, ctx, cancel := newPhaseDeadline(context.Background(), "Down", d.DownTimeout)
+	defer cancel()
+	if err := deleteResources(ctx, d.infraManager, d.clusterManager, d.addonManager, d.nodeManager, d.k8sClient, &d.deployerOptions, deadline); err != nil {
+		return fmt.Errorf("%w (failed to delete the resources created so far: %v)", upErr, deadline.err(ctx, err))
+	}
+	return upErr
+}
+
+// up creates the cluster and its nodes, aborting when ctx is done
+func (d *deployer) up(ctx context.Context, deadline *phaseDeadline) error {
 	if d.deployerOptions.StaticClusterName == "" {
-		if infra, err := d.infraManager.createInfrastructureStack(&d.deployerOptions); err != nil {
+		if err := deadline.enter(ctx, "infrastructure"); err != nil {
+			return err
+		}
+		if infra, err := d.infraManager.createInfrastructureStack(ctx, &d.deployerOptions); err != nil {
 			return err
 		} else {
 			d.infra = infra
 		}
 	}
-	cluster, err := d.clusterManager.getOrCreateCluster(d.infra, &d.deployerOptions)
+	if err := deadline.enter(ctx, "cluster"); err != nil {
+		return err
+	}
+	cluster, err := d.clusterManager.getOrCreateCluster(ctx, d.infra, &d.deployerOptions)
 	if err != nil {
 		return err
 	}
@@ -195,6 +278,9 @@ func (d *deployer) Up() error {
 	if err != nil {
 		return err
 	}
+	if d.ClusterReadyWebhookURL != "" {
+		d.notifyClusterReady()
+	}
 	if d.deployerOptions.StaticClusterName != "" {
 		klog.Infof("inited k8sclient, skip the rest resource creation for static cluster")
 		d.staticClusterManager.SetK8sClient(kubeconfig)
@@ -206,7 +292,7 @@ func (d *deployer) Up() error {
 		return nil
 	}
 	if d.UnmanagedNodes {
-		if err := d.k8sClient.createAWSAuthConfigMap(d.NodeNameStrategy, d.infra.nodeRoleARN); err != nil {
+		if err := d.k8sClient.createAWSAuthConfigMap(ctx, d.NodeNameStrategy, d.infra.nodeRoleARN); err != nil {
 			return err
 		}
 	}
@@ -214,7 +300,13 @@ func (d *deployer) Up() error {
 		d.ExpectedAMI = d.AMI
 	}
 
-	if err := d.addonManager.createAddons(d.infra, d.cluster, &d.deployerOptions); err != nil {
+	if err := deadline.enter(ctx, "addons"); err != nil {
+		return err
+	}
+	if err := d.addonManager.createAddons(ctx, d.infra, d.cluster, &d.deployerOptions); err != nil {
+		return err
+	}
+	if err := d.k8sClient.waitForCRDsEstablished(ctx, requiredCRDs(d.Addons), crdEstablishedTimeout); err != nil {
 		return err
 	}
 	if d.deployerOptions.TuneVPCCNI {
@@ -222,13 +314,25 @@ func (d *deployer) Up() error {
 			return err
 		}
 	}
-	if err := d.nodeManager.createNodes(d.infra, d.cluster, &d.deployerOptions, d.k8sClient); err != nil {
+	if err := deadline.enter(ctx, "nodes"); err != nil {
+		return err
+	}
+	if err := d.nodeManager.createNodes(ctx, d.infra, d.cluster, &d.deployerOptions, d.k8sClient); err != nil {
 		return err
 	}
 	if !d.SkipNodeReadinessChecks {
-		if err := d.k8sClient.waitForReadyNodes(d.Nodes, d.NodeReadyTimeout); err != nil {
+		if err := deadline.enter(ctx, "node readiness"); err != nil {
 			return err
 		}
+		readyNodes := readyNodeThreshold(d.Nodes, d.NodeReadyPercent)
+		if err := d.k8sClient.waitForReadyNodes(ctx, readyNodes, d.NodeReadyTimeout); err != nil {
+			return err
+		}
+		if readyNodes < d.Nodes {
+			if err := d.k8sClient.logNotReadyNodes(d.Nodes); err != nil {
+				klog.Warningf("failed to list nodes that are not ready: %v", err)
+			}
+		}
 		if d.EmitMetrics {
 			if err := d.k8sClient.emitNodeMetrics(d.metrics, d.awsClients.EC2()); err != nil {
 				return err
@@ -239,10 +343,21 @@ func (d *deployer) Up() error {
 			// don't return err, this isn't critical
 		}
 	}
+	if d.VerifyDNS {
+		if err := deadline.enter(ctx, "DNS verification"); err != nil {
+			return err
+		}
+		if err := d.k8sClient.verifyDNS(ctx, &d.deployerOptions); err != nil {
+			return err
+		}
+	}
 
 	if d.DeployCloudwatchInfra {
+		if err := deadline.enter(ctx, "CloudWatch infrastructure"); err != nil {
+			return err
+		}
 		klog.Infof("Setting up CloudWatch infrastructure...")
-		if roleArn, err := d.infraManager.createCloudWatchInfrastructureStack(d.cluster.name); err != nil {
+		if roleArn, err := d.infraManager.createCloudWatchInfrastructureStack(ctx, d.cluster.name); err != nil {
 			klog.Errorf("CloudWatch infrastructure setup failed: %v", err)
 			return err
 		} else {
@@ -294,6 +409,25 @@ func (d *deployer) verifyUpFlags() error {
 	if d.NodeReadyTimeout == 0 {
 		d.NodeReadyTimeout = time.Minute * 5
 	}
+	if d.NodeReadyPercent == 0 {
+		d.NodeReadyPercent = 100
+	} else if d.NodeReadyPercent < 0 || d.NodeReadyPercent > 100 {
+		return fmt.Errorf("--node-ready-threshold-percent must be between 1 and 100")
+	}
+	if _, err := parsePodLabels(d.PodLabels); err != nil {
+		return fmt.Errorf("--pod-labels are invalid: %v", err)
+	}
+	if _, err := parseKeyValuePairs(d.PodAnnotations); err != nil {
+		return fmt.Errorf("--pod-annotations are invalid: %v", err)
+	}
+	if _, err := verifyRunDirRetention(d.RunDirRetentionMaxAge, d.RunDirRetentionMaxSize); err != nil {
+		return err
+	}
+	if d.ClusterReadyWebhookURL != "" {
+		if u, err := url.Parse(d.ClusterReadyWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("--cluster-ready-webhook-url must be an http or https URL: %s", d.ClusterReadyWebhookURL)
+		}
+	}
 	if d.StaticClusterName != "" {
 		klog.Infof("Skip configuration for static cluster")
 		return nil
@@ -347,6 +481,11 @@ func (d *deployer) verifyUpFlags() error {
 		// this must be prepended to the list in order to respect user overrides.
 		d.deployerOptions.Addons = slices.Insert(d.deployerOptions.Addons, 0, "eks-pod-identity-agent:default")
 	}
+	addons, err := applyAddonOverrides(d.deployerOptions.Addons, os.Environ())
+	if err != nil {
+		return err
+	}
+	d.deployerOptions.Addons = addons
 	return nil
 }
 
@@ -374,24 +513,71 @@ func (d *deployer) Down() error {
 	if d.deployerOptions.StaticClusterName != "" {
 		return d.staticClusterManager.TearDownNodeForStaticCluster()
 	}
-	return deleteResources(d.infraManager, d.clusterManager, d.nodeManager, d.k8sClient, &d.deployerOptions)
+	var nodeRoleName string
+	if d.VerifyDown {
+		nodeRoleName = d.downNodeRoleName()
+	}
+	deadline, ctx, cancel := newPhaseDeadline(context.Background(), "Down", d.DownTimeout)
+	defer cancel()
+	var clusterTags map[string]string
+	var clusterTagsErr error
+	if d.DeleteLogGroups {
+		clusterTags, clusterTagsErr = getClusterTags(ctx, d.awsClients, d.clusterManager.resourceID)
+	}
+	if err := deleteResources(ctx, d.infraManager, d.clusterManager, d.addonManager, d.nodeManager, d.k8sClient, &d.deployerOptions, deadline); err != nil {
+		return deadline.err(ctx, err)
+	}
+	if d.DeleteLogGroups {
+		if clusterTagsErr != nil {
+			klog.Warningf("the cluster's tags are unknown, its log groups will not be deleted: %v", clusterTagsErr)
+		} else if err := deadline.enter(ctx, "log groups"); err != nil {
+			return deadline.err(ctx, err)
+		} else if err := deleteClusterLogGroups(ctx, d.awsClients, d.clusterManager.resourceID, clusterTags); err != nil {
+			return deadline.err(ctx, err)
+		}
+	}
+	if d.VerifyDown {
+		return deadline.err(ctx, d.verifyDown(ctx, deadline, nodeRoleName))
+	}
+	return nil
 }
 
-func deleteResources(im *InfrastructureManager, cm *ClusterManager, nm *nodeManager, k8sClient *k8sClient /* nillable */, opts *deployerOptions /* nillable */) error {
-	if err := im.deleteCloudWatchInfrastructureStack(); err != nil {
+func deleteResources(ctx context.Context, im *InfrastructureManager, cm *ClusterManager, am *AddonManager, nm *nodeManager, k8sClient *k8sClient /* nillable */, opts *deployerOptions /* nillable */, deadline *phaseDeadline /* nillable */) error {
+	if err := deadline.enter(ctx, "CloudWatch infrastructure"); err != nil {
+		return err
+	}
+	if err := im.deleteCloudWatchInfrastructureStack(ctx); err != nil {
+		return err
+	}
+	if err := deadline.enter(ctx, "nodes"); err != nil {
+		return err
+	}
+	if err := nm.deleteNodes(ctx, k8sClient, opts); err != nil {
 		return err
 	}
-	if err := nm.deleteNodes(k8sClient, opts); err != nil {
+	if err := deadline.enter(ctx, "addons"); err != nil {
+		return err
+	}
+	if err := am.deleteAddons(ctx, cm.resourceID); err != nil {
 		return err
 	}
 	// the EKS-managed cluster security group may be associated with a leaked ENI
 	// so we need to make sure we've deleted leaked ENIs before we delete the cluster
 	// otherwise, the cluster security group will be left behind and will block deletion of our VPC
-	if err := im.deleteLeakedENIs(); err != nil {
+	if err := deadline.enter(ctx, "leaked ENIs"); err != nil {
+		return err
+	}
+	if err := im.deleteLeakedENIs(ctx); err != nil {
+		return err
+	}
+	if err := deadline.enter(ctx, "cluster"); err != nil {
+		return err
+	}
+	if err := cm.deleteCluster(ctx); err != nil {
 		return err
 	}
-	if err := cm.deleteCluster(); err != nil {
+	if err := deadline.enter(ctx, "infrastructure"); err != nil {
 		return err
 	}
-	return im.deleteInfrastructureStack()
+	return im.deleteInfrastructureStack(ctx)
 }
@@ -0,0 +1,87 @@
+package eksapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const (
+	dnsCheckPodName = "dns-check"
+	dnsCheckTimeout = 5 * time.Minute
+)
+
+// dnsCheckNames are resolved from inside the cluster: the API server's service, and a name only an upstream resolver knows
+var dnsCheckNames = []string{
+	"kubernetes.default.svc.cluster.local",
+	"public.ecr.aws",
+}
+
+// verifyDNS runs a pod that resolves in-cluster and external names, which catches CoreDNS or CNI breakage
+// that doesn't show up in node readiness
+func (k *k8sClient) verifyDNS(ctx context.Context, opts *deployerOptions) error {
+	var commands []string
+	for _, name := range dnsCheckNames {
+		commands = append(commands, fmt.Sprintf("getent hosts %s", name))
+	}
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "main",
+					Image:   "public.ecr.aws/amazonlinux/amazonlinux:2023",
+					Command: []string{"sh", "-c", strings.Join(commands, " && ")},
+				},
+			},
+		},
+	}
+	if err := stampPodMetadata(&template, opts); err != nil {
+		return err
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dnsCheckPodName,
+			Namespace:   "default",
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: template.Spec,
+	}
+	klog.Infof("verifying DNS resolution of %v...", dnsCheckNames)
+	if _, err := k.clientset.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create DNS check pod: %v", err)
+	}
+	defer func() {
+		if err := k.clientset.CoreV1().Pods("default").Delete(context.TODO(), dnsCheckPodName, *metav1.NewDeleteOptions(0)); err != nil {
+			klog.Warningf("failed to delete DNS check pod: %v", err)
+		}
+	}()
+	var phase corev1.PodPhase
+	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, dnsCheckTimeout, true, func(ctx context.Context) (bool, error) {
+		p, err := k.clientset.CoreV1().Pods("default").Get(ctx, dnsCheckPodName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase = p.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return fmt.Errorf("DNS check pod did not complete (phase: %s): %v", phase, err)
+	}
+	if phase == corev1.PodFailed {
+		logs, err := k.clientset.CoreV1().Pods("default").GetLogs(dnsCheckPodName, &corev1.PodLogOptions{}).DoRaw(ctx)
+		if err != nil {
+			klog.Warningf("failed to get DNS check pod logs: %v", err)
+		}
+		return fmt.Errorf("DNS resolution failed for one of %v: %s", dnsCheckNames, strings.TrimSpace(string(logs)))
+	}
+	klog.Infof("verified DNS resolution")
+	return nil
+}
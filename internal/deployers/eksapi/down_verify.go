@@ -0,0 +1,137 @@
+package eksapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/internal/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cloudformationtypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
+	"k8s.io/klog/v2"
+)
+
+const (
+	downVerifyInterval = 15 * time.Second
+	downVerifyTimeout  = 10 * time.Minute
+)
+
+// downNodeRoleName returns the name of the infrastructure stack's node role, so it can be checked after the stack is gone.
+// Returns an empty string if the stack can't be described.
+func (d *deployer) downNodeRoleName() string {
+	if d.infra != nil {
+		return d.infra.nodeRoleName
+	}
+	infra, err := d.infraManager.getInfrastructureStackResources(context.TODO())
+	if err != nil {
+		klog.Warningf("failed to get infrastructure stack resources, the node role will not be verified: %v", err)
+		return ""
+	}
+	return infra.nodeRoleName
+}
+
+// verifyDown waits for the cluster, infrastructure stack, and node role to be gone after Down.
+// If any remain, the deletion is retried once before reporting them. Both are bounded by Down's deadline.
+func (d *deployer) verifyDown(ctx context.Context, deadline *phaseDeadline /* nillable */, nodeRoleName string) error {
+	if err := deadline.enter(ctx, "verify down"); err != nil {
+		return err
+	}
+	residue, err := d.waitForNoDownResidue(ctx, nodeRoleName)
+	if err != nil {
+		return err
+	}
+	if len(residue) == 0 {
+		klog.Infof("verified that the cluster, infrastructure stack, and node role are deleted")
+		return nil
+	}
+	klog.Warningf("resources remain after Down, retrying deletion: %s", strings.Join(residue, ", "))
+	if err := deleteResources(ctx, d.infraManager, d.clusterManager, d.addonManager, d.nodeManager, d.k8sClient, &d.deployerOptions, deadline); err != nil {
+		return fmt.Errorf("failed to retry deletion: %w", err)
+	}
+	if err := deadline.enter(ctx, "verify down"); err != nil {
+		return err
+	}
+	residue, err = d.waitForNoDownResidue(ctx, nodeRoleName)
+	if err != nil {
+		return err
+	}
+	if len(residue) > 0 {
+		return fmt.Errorf("resources remain after Down: %s", strings.Join(residue, ", "))
+	}
+	return nil
+}
+
+// waitForNoDownResidue polls until the resources are gone, returning those that still remain when it times out
+func (d *deployer) waitForNoDownResidue(ctx context.Context, nodeRoleName string) ([]string, error) {
+	var residue []string
+	var lastErr error
+	err := util.PollUntil(ctx, downVerifyInterval, downVerifyTimeout, func(ctx context.Context) (bool, error) {
+		remaining, err := d.getDownResidue(ctx, nodeRoleName)
+		if err != nil {
+			klog.Warningf("failed to check for resources remaining after Down: %v", err)
+			lastErr = err
+			return false, nil
+		}
+		residue = remaining
+		lastErr = nil
+		return len(residue) == 0, nil
+	})
+	if err != nil && lastErr != nil {
+		return nil, fmt.Errorf("failed to check for resources remaining after Down: %v", lastErr)
+	}
+	// the resources that remain are unknown if ctx was done before they could be checked
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check for resources remaining after Down: %w", err)
+	}
+	return residue, nil
+}
+
+// getDownResidue describes each resource Down should have deleted, and returns a description of those that still exist
+func (d *deployer) getDownResidue(ctx context.Context, nodeRoleName string) ([]string, error) {
+	var residue []string
+	cluster, err := d.awsClients.EKS().DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(d.clusterManager.resourceID),
+	})
+	if err != nil {
+		var notFound *ekstypes.ResourceNotFoundException
+		if !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("failed to describe cluster: %v", err)
+		}
+	} else {
+		residue = append(residue, fmt.Sprintf("cluster %s (%s)", d.clusterManager.resourceID, cluster.Cluster.Status))
+	}
+	stacks, err := d.awsClients.CFN().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(d.infraManager.resourceID),
+	})
+	if err != nil {
+		// CloudFormation reports a stack that doesn't exist as a ValidationError
+		var apierr smithy.APIError
+		if !errors.As(err, &apierr) || apierr.ErrorCode() != "ValidationError" {
+			return nil, fmt.Errorf("failed to describe infrastructure stack: %v", err)
+		}
+	} else if len(stacks.Stacks) > 0 && stacks.Stacks[0].StackStatus != cloudformationtypes.StackStatusDeleteComplete {
+		residue = append(residue, fmt.Sprintf("infrastructure stack %s (%s)", d.infraManager.resourceID, stacks.Stacks[0].StackStatus))
+	}
+	if nodeRoleName != "" {
+		_, err := d.awsClients.IAM().GetRole(ctx, &iam.GetRoleInput{
+			RoleName: aws.String(nodeRoleName),
+		})
+		if err != nil {
+			var noSuchEntity *iamtypes.NoSuchEntityException
+			if !errors.As(err, &noSuchEntity) {
+				return nil, fmt.Errorf("failed to get node role: %v", err)
+			}
+		} else {
+			residue = append(residue, fmt.Sprintf("node role %s", nodeRoleName))
+		}
+	}
+	return residue, nil
+}
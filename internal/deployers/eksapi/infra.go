@@ -89,16 +89,16 @@ func (i *Infrastructure) subnets() []string {
 	return append(i.subnetsPublic, i.subnetsPrivate...)
 }
 
-func (m *InfrastructureManager) createInfrastructureStack(opts *deployerOptions) (*Infrastructure, error) {
+func (m *InfrastructureManager) createInfrastructureStack(ctx context.Context, opts *deployerOptions) (*Infrastructure, error) {
 	var subnetAzs []string
 	if opts.CapacityReservation {
-		azs, err := m.getAZsWithCapacity(opts)
+		azs, err := m.getAZsWithCapacity(ctx, opts)
 		if err != nil {
 			return nil, err
 		}
 		subnetAzs = azs
 	} else if len(opts.InstanceTypes) > 0 {
-		azs, err := m.getRankedAZsForInstanceTypes(opts)
+		azs, err := m.getRankedAZsForInstanceTypes(ctx, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -112,7 +112,7 @@ func (m *InfrastructureManager) createInfrastructureStack(opts *deployerOptions)
 	// TODO: create a subnet in every AZ. today we need exactly 2 AZs for the subnets.
 	const numInfraAZs = 2
 
-	subnetAzs, err := m.normalizeAZs(opts, subnetAzs, numInfraAZs)
+	subnetAzs, err := m.normalizeAZs(ctx, opts, subnetAzs, numInfraAZs)
 	if err != nil {
 		return nil, err
 	}
@@ -156,13 +156,13 @@ func (m *InfrastructureManager) createInfrastructureStack(opts *deployerOptions)
 		}
 	}
 	klog.Infof("creating infrastructure stack...")
-	out, err := m.clients.CFN().CreateStack(context.TODO(), &input)
+	out, err := m.clients.CFN().CreateStack(ctx, &input)
 	if err != nil {
 		return nil, err
 	}
 	klog.Infof("waiting for infrastructure stack to be created: %s", *out.StackId)
 	err = cloudformation.NewStackCreateCompleteWaiter(m.clients.CFN()).
-		Wait(context.TODO(),
+		Wait(ctx,
 			&cloudformation.DescribeStacksInput{
 				StackName: out.StackId,
 			},
@@ -171,7 +171,7 @@ func (m *InfrastructureManager) createInfrastructureStack(opts *deployerOptions)
 		return nil, fmt.Errorf("failed to wait for infrastructure stack creation: %w", err)
 	}
 	klog.Infof("getting infrastructure stack resources: %s", *out.StackId)
-	infra, err := m.getInfrastructureStackResources()
+	infra, err := m.getInfrastructureStackResources(ctx)
 	infra.availabilityZones = subnetAzs
 	if err != nil {
 		return nil, fmt.Errorf("failed to get infrastructure stack resources: %w", err)
@@ -181,8 +181,8 @@ func (m *InfrastructureManager) createInfrastructureStack(opts *deployerOptions)
 	return infra, nil
 }
 
-func (m *InfrastructureManager) getInfrastructureStackResources() (*Infrastructure, error) {
-	stack, err := m.clients.CFN().DescribeStacks(context.TODO(), &cloudformation.DescribeStacksInput{
+func (m *InfrastructureManager) getInfrastructureStackResources(ctx context.Context) (*Infrastructure, error) {
+	stack, err := m.clients.CFN().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
 		StackName: aws.String(m.resourceID),
 	})
 	if err != nil {
@@ -218,8 +218,8 @@ func (m *InfrastructureManager) getInfrastructureStackResources() (*Infrastructu
 	return &infra, nil
 }
 
-func (m *InfrastructureManager) deleteInfrastructureStack() error {
-	infra, err := m.getInfrastructureStackResources()
+func (m *InfrastructureManager) deleteInfrastructureStack(ctx context.Context) error {
+	infra, err := m.getInfrastructureStackResources(ctx)
 	if err != nil {
 		var notFound *cloudformationtypes.StackNotFoundException
 		if errors.As(err, &notFound) {
@@ -228,14 +228,14 @@ func (m *InfrastructureManager) deleteInfrastructureStack() error {
 		}
 		return err
 	}
-	if err := m.deleteLeakedInstanceProfiles(infra); err != nil {
+	if err := m.deleteLeakedInstanceProfiles(ctx, infra); err != nil {
 		return err
 	}
 	input := cloudformation.DeleteStackInput{
 		StackName: aws.String(m.resourceID),
 	}
 	klog.Infof("deleting infrastructure stack: %s", m.resourceID)
-	_, err = m.clients.CFN().DeleteStack(context.TODO(), &input)
+	_, err = m.clients.CFN().DeleteStack(ctx, &input)
 	if err != nil {
 		var notFound *cloudformationtypes.StackNotFoundException
 		if errors.As(err, &notFound) {
@@ -246,7 +246,7 @@ func (m *InfrastructureManager) deleteInfrastructureStack() error {
 	}
 	klog.Infof("waiting for infrastructure stack to be deleted: %s", m.resourceID)
 	err = cloudformation.NewStackDeleteCompleteWaiter(m.clients.CFN()).
-		Wait(context.TODO(),
+		Wait(ctx,
 			&cloudformation.DescribeStacksInput{
 				StackName: aws.String(m.resourceID),
 			},
@@ -264,13 +264,13 @@ func (m *InfrastructureManager) deleteInfrastructureStack() error {
 // deleteLeakedIntanceProfiles deletes any instance profiles to which the node role is attached,
 // because this will block node role deletion (and deletion of the infrastructure stack).
 // For example, when --auto-mode is used, an instance profile will be created for us and won't be deleted automatically with the cluster.
-func (m *InfrastructureManager) deleteLeakedInstanceProfiles(infra *Infrastructure) error {
+func (m *InfrastructureManager) deleteLeakedInstanceProfiles(ctx context.Context, infra *Infrastructure) error {
 	if infra.nodeRoleName == "" {
 		// if the infra stack failed to create, it could end up in a weird state with no node role
 		// we know there aren't any instance profiles in that case, so all good
 		return nil
 	}
-	out, err := m.clients.IAM().ListInstanceProfilesForRole(context.TODO(), &iam.ListInstanceProfilesForRoleInput{
+	out, err := m.clients.IAM().ListInstanceProfilesForRole(ctx, &iam.ListInstanceProfilesForRoleInput{
 		RoleName: aws.String(infra.nodeRoleName),
 	})
 	if err != nil {
@@ -282,7 +282,7 @@ func (m *InfrastructureManager) deleteLeakedInstanceProfiles(infra *Infrastructu
 	} else if len(out.InstanceProfiles) > 0 {
 		var deletedInstanceProfiles []string
 		for _, instanceProfile := range out.InstanceProfiles {
-			_, err := m.clients.IAM().RemoveRoleFromInstanceProfile(context.TODO(), &iam.RemoveRoleFromInstanceProfileInput{
+			_, err := m.clients.IAM().RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
 				RoleName:            aws.String(infra.nodeRoleName),
 				InstanceProfileName: instanceProfile.InstanceProfileName,
 			})
@@ -294,7 +294,7 @@ func (m *InfrastructureManager) deleteLeakedInstanceProfiles(infra *Infrastructu
 				}
 				return fmt.Errorf("failed to remove node role %s from instance profile: %s: %v", infra.nodeRoleName, aws.ToString(instanceProfile.InstanceProfileName), err)
 			}
-			_, err = m.clients.IAM().DeleteInstanceProfile(context.TODO(), &iam.DeleteInstanceProfileInput{
+			_, err = m.clients.IAM().DeleteInstanceProfile(ctx, &iam.DeleteInstanceProfileInput{
 				InstanceProfileName: instanceProfile.InstanceProfileName,
 			})
 			if err != nil {
@@ -314,8 +314,8 @@ func (m *InfrastructureManager) deleteLeakedInstanceProfiles(infra *Infrastructu
 
 // deleteLeakedENIs deletes Elastic Network Interfaces that may have been allocated (and left behind) by the VPC CNI.
 // These leaked ENIs will prevent deletion of their associated subnets and security groups.
-func (m *InfrastructureManager) deleteLeakedENIs() error {
-	infra, err := m.getInfrastructureStackResources()
+func (m *InfrastructureManager) deleteLeakedENIs(ctx context.Context) error {
+	infra, err := m.getInfrastructureStackResources(ctx)
 	if err != nil {
 		var notFound *cloudformationtypes.StackNotFoundException
 		if errors.As(err, &notFound) {
@@ -323,7 +323,7 @@ func (m *InfrastructureManager) deleteLeakedENIs() error {
 		}
 		return fmt.Errorf("failed to get infrastructure stack resources: %w", err)
 	}
-	enis, err := m.getVPCCNINetworkInterfaceIds(infra.vpc)
+	enis, err := m.getVPCCNINetworkInterfaceIds(ctx, infra.vpc)
 	if err != nil {
 		return err
 	}
@@ -331,10 +331,10 @@ func (m *InfrastructureManager) deleteLeakedENIs() error {
 		return nil
 	}
 	klog.Infof("waiting for %d leaked ENI(s) to become available: %v", len(enis), enis)
-	if err := ec2.NewNetworkInterfaceAvailableWaiter(m.clients.EC2()).Wait(context.TODO(), &ec2.DescribeNetworkInterfacesInput{
+	if err := ec2.NewNetworkInterfaceAvailableWaiter(m.clients.EC2()).Wait(ctx, &ec2.DescribeNetworkInterfacesInput{
 		NetworkInterfaceIds: enis,
 	}, networkInterfaceDetachmentTimeout); err != nil {
-		refreshedENIs, err2 := m.getVPCCNINetworkInterfaceIds(infra.vpc)
+		refreshedENIs, err2 := m.getVPCCNINetworkInterfaceIds(ctx, infra.vpc)
 		if err2 != nil {
 			return fmt.Errorf("waiter failed, and re-checking ENIs also failed: %w", err2)
 		}
@@ -346,7 +346,7 @@ func (m *InfrastructureManager) deleteLeakedENIs() error {
 	}
 	for _, eni := range enis {
 		klog.Infof("deleting leaked ENI: %s", eni)
-		_, err := m.clients.EC2().DeleteNetworkInterface(context.TODO(), &ec2.DeleteNetworkInterfaceInput{
+		_, err := m.clients.EC2().DeleteNetworkInterface(ctx, &ec2.DeleteNetworkInterfaceInput{
 			NetworkInterfaceId: aws.String(eni),
 		})
 		if err != nil {
@@ -359,7 +359,7 @@ func (m *InfrastructureManager) deleteLeakedENIs() error {
 }
 
 // getVPCCNINetworkInterfaceIds returns the IDs of ENIs in the specified VPC that were created by the VPC CNI
-func (m *InfrastructureManager) getVPCCNINetworkInterfaceIds(vpcId string) ([]string, error) {
+func (m *InfrastructureManager) getVPCCNINetworkInterfaceIds(ctx context.Context, vpcId string) ([]string, error) {
 	paginator := ec2.NewDescribeNetworkInterfacesPaginator(m.clients.EC2(), &ec2.DescribeNetworkInterfacesInput{
 		Filters: []ec2types.Filter{
 			{
@@ -378,7 +378,7 @@ func (m *InfrastructureManager) getVPCCNINetworkInterfaceIds(vpcId string) ([]st
 	})
 	var enis []string
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to describe ENIs: %w", err)
 		}
@@ -392,8 +392,8 @@ func (m *InfrastructureManager) getVPCCNINetworkInterfaceIds(vpcId string) ([]st
 // normalizeAZs removes availability zones that don't meet launch requirements
 // for instances and ensures that the resulting list containers enough AZs to
 // satisfy the deployment.
-func (m *InfrastructureManager) normalizeAZs(opts *deployerOptions, subnetAZs []string, expectedCount int) ([]string, error) {
-	azs, err := m.clients.EC2().DescribeAvailabilityZones(context.TODO(), &ec2.DescribeAvailabilityZonesInput{
+func (m *InfrastructureManager) normalizeAZs(ctx context.Context, opts *deployerOptions, subnetAZs []string, expectedCount int) ([]string, error) {
+	azs, err := m.clients.EC2().DescribeAvailabilityZones(ctx, &ec2.DescribeAvailabilityZonesInput{
 		Filters: []ec2types.Filter{
 			{
 				Name:   aws.String("zone-type"),
@@ -445,8 +445,8 @@ func (m *InfrastructureManager) normalizeAZs(opts *deployerOptions, subnetAZs []
 
 // getAZsWithInstanceTypes returns the availability zones ordered decreasingly by the number of
 // requested instance types they support
-func (m *InfrastructureManager) getRankedAZsForInstanceTypes(opts *deployerOptions) ([]string, error) {
-	offerings, err := m.clients.EC2().DescribeInstanceTypeOfferings(context.TODO(), &ec2.DescribeInstanceTypeOfferingsInput{
+func (m *InfrastructureManager) getRankedAZsForInstanceTypes(ctx context.Context, opts *deployerOptions) ([]string, error) {
+	offerings, err := m.clients.EC2().DescribeInstanceTypeOfferings(ctx, &ec2.DescribeInstanceTypeOfferingsInput{
 		LocationType: ec2types.LocationTypeAvailabilityZone,
 		Filters: []ec2types.Filter{
 			{
@@ -472,7 +472,7 @@ func (m *InfrastructureManager) getRankedAZsForInstanceTypes(opts *deployerOptio
 	return azs, nil
 }
 
-func (m *InfrastructureManager) getAZsWithCapacity(opts *deployerOptions) ([]string, error) {
+func (m *InfrastructureManager) getAZsWithCapacity(ctx context.Context, opts *deployerOptions) ([]string, error) {
 	// TODO: consolidate this with the CapacityReservation logic in node.go
 	var subnetAzs []string
 	describeReservationsInput := ec2.DescribeCapacityReservationsInput{
@@ -490,7 +490,7 @@ func (m *InfrastructureManager) getAZsWithCapacity(opts *deployerOptions) ([]str
 	if opts.TargetCapacityReservationId != "" {
 		describeReservationsInput.CapacityReservationIds = []string{opts.TargetCapacityReservationId}
 	}
-	capacityReservations, err := m.clients.EC2().DescribeCapacityReservations(context.TODO(), &describeReservationsInput)
+	capacityReservations, err := m.clients.EC2().DescribeCapacityReservations(ctx, &describeReservationsInput)
 	if err != nil {
 		return nil, err
 	}
@@ -508,10 +508,10 @@ func getCloudWatchStackName(resourceID string) (string, string) {
 	return fmt.Sprintf("%s-cw", resourceID), clusterUUID
 }
 
-func (m *InfrastructureManager) createCloudWatchInfrastructureStack(clusterName string) (string, error) {
+func (m *InfrastructureManager) createCloudWatchInfrastructureStack(ctx context.Context, clusterName string) (string, error) {
 	stackName, clusterUUID := getCloudWatchStackName(clusterName)
 	klog.Infof("creating CloudWatch infrastructure stack: %s", stackName)
-	out, err := m.clients.CFN().CreateStack(context.TODO(), &cloudformation.CreateStackInput{
+	out, err := m.clients.CFN().CreateStack(ctx, &cloudformation.CreateStackInput{
 		StackName:    aws.String(stackName),
 		TemplateBody: aws.String(templates.CloudWatchInfra),
 		Capabilities: []cloudformationtypes.Capability{cloudformationtypes.CapabilityCapabilityNamedIam},
@@ -532,7 +532,7 @@ func (m *InfrastructureManager) createCloudWatchInfrastructureStack(clusterName
 
 	klog.Infof("waiting for CloudWatch infrastructure stack to be created: %s", *out.StackId)
 	if err := cloudformation.NewStackCreateCompleteWaiter(m.clients.CFN()).
-		Wait(context.TODO(),
+		Wait(ctx,
 			&cloudformation.DescribeStacksInput{
 				StackName: out.StackId,
 			},
@@ -541,7 +541,7 @@ func (m *InfrastructureManager) createCloudWatchInfrastructureStack(clusterName
 	}
 
 	// Get the CloudWatch role ARN from stack outputs
-	stack, err := m.clients.CFN().DescribeStacks(context.TODO(), &cloudformation.DescribeStacksInput{
+	stack, err := m.clients.CFN().DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
 		StackName: out.StackId,
 	})
 	if err != nil {
@@ -558,11 +558,11 @@ func (m *InfrastructureManager) createCloudWatchInfrastructureStack(clusterName
 	return "", fmt.Errorf("CloudWatch role ARN not found in stack outputs")
 }
 
-func (m *InfrastructureManager) deleteCloudWatchInfrastructureStack() error {
+func (m *InfrastructureManager) deleteCloudWatchInfrastructureStack(ctx context.Context) error {
 	stackName, _ := getCloudWatchStackName(m.resourceID)
 
 	klog.Infof("deleting CloudWatch infrastructure stack: %s", stackName)
-	if _, err := m.clients.CFN().DeleteStack(context.TODO(), &cloudformation.DeleteStackInput{
+	if _, err := m.clients.CFN().DeleteStack(ctx, &cloudformation.DeleteStackInput{
 		StackName: aws.String(stackName),
 	}); err != nil {
 		var notFound *cloudformationtypes.StackNotFoundException
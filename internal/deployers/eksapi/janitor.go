@@ -112,9 +112,10 @@ func (j *janitor) sweepWorker(wg *sync.WaitGroup, stackQueue <-chan cloudformati
 		clients := j.awsClientsForStack(stack)
 		infraManager := NewInfrastructureManager(clients, resourceID, j.metrics)
 		clusterManager := NewClusterManager(clients, resourceID)
+		addonManager := NewAddonManager(clients)
 		nodeManager := NewNodeManager(clients, resourceID)
 		klog.Infof("deleting resources (%v old): %s", resourceAge, resourceID)
-		if err := deleteResources(infraManager, clusterManager, nodeManager, nil /* k8sClient */, nil /* deployerOptions */); err != nil {
+		if err := deleteResources(context.TODO(), infraManager, clusterManager, addonManager, nodeManager, nil /* k8sClient */, nil /* deployerOptions */, nil /* deadline */); err != nil {
 			errChan <- fmt.Errorf("failed to delete resources: %s: %v", resourceID, err)
 		}
 	}
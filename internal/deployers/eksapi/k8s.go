@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -52,10 +53,10 @@ func newK8sClient(kubeconfigPath string) (*k8sClient, error) {
 	}, nil
 }
 
-func (k *k8sClient) waitForReadyNodes(nodeCount int, timeout time.Duration) error {
+func (k *k8sClient) waitForReadyNodes(ctx context.Context, nodeCount int, timeout time.Duration) error {
 	klog.Infof("waiting up to %v for %d node(s) to be ready...", timeout, nodeCount)
 	readyNodes := sets.NewString()
-	watcher, err := k.clientset.CoreV1().Nodes().Watch(context.TODO(), metav1.ListOptions{})
+	watcher, err := k.clientset.CoreV1().Nodes().Watch(ctx, metav1.ListOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create node watcher: %v", err)
 	}
@@ -65,7 +66,8 @@ func (k *k8sClient) waitForReadyNodes(nodeCount int, timeout time.Duration) erro
 		return fmt.Errorf("failed to get ready nodes: %v", err)
 	}
 	counter := len(initialReadyNodes)
-	ctx, _ := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	for {
 		select {
 		case event, ok := <-watcher.ResultChan():
@@ -98,22 +100,22 @@ func (k *k8sClient) waitForReadyNodes(nodeCount int, timeout time.Duration) erro
 	return nil
 }
 
-func (k *k8sClient) waitForNodeDeletion(timeout time.Duration) error {
+func (k *k8sClient) waitForNodeDeletion(ctx context.Context, timeout time.Duration) error {
 	klog.Infof("waiting up to %v for node(s) to be deleted...", timeout)
 	nodes := sets.NewString()
-	watcher, err := k.clientset.CoreV1().Nodes().Watch(context.TODO(), metav1.ListOptions{})
+	watcher, err := k.clientset.CoreV1().Nodes().Watch(ctx, metav1.ListOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create node watcher: %v", err)
 	}
 	defer watcher.Stop()
-	initialNodes, err := k.clientset.CoreV1().Nodes().List(context.TODO(), v1.ListOptions{})
+	initialNodes, err := k.clientset.CoreV1().Nodes().List(ctx, v1.ListOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to list nodes: %v", err)
 	}
 	for _, node := range initialNodes.Items {
 		nodes.Insert(node.Name)
 	}
-	ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+	ctx, cancelFunc := context.WithTimeout(ctx, timeout)
 	defer cancelFunc()
 	for {
 		select {
@@ -151,6 +153,31 @@ func (k *k8sClient) waitForNodeDeletion(timeout time.Duration) error {
 	return nil
 }
 
+// logNotReadyNodes reports the nodes that aren't Ready yet, when we've proceeded without waiting for all of them
+func (k *k8sClient) logNotReadyNodes(expectedNodeCount int) error {
+	nodes, err := k.clientset.CoreV1().Nodes().List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var readyCount int
+	var notReadyNodes []string
+	for _, node := range nodes.Items {
+		if isNodeReady(&node) {
+			readyCount++
+		} else {
+			notReadyNodes = append(notReadyNodes, node.Name)
+		}
+	}
+	klog.Infof("proceeding with %d/%d node(s) ready, %d node(s) not registered, not ready: %v", readyCount, expectedNodeCount, max(expectedNodeCount-len(nodes.Items), 0), notReadyNodes)
+	return nil
+}
+
+// readyNodeThreshold returns the number of nodes that must be Ready for the nodegroup to be considered ready
+func readyNodeThreshold(nodeCount int, thresholdPercent int) int {
+	// round up, so we never accept fewer nodes than the percentage asks for
+	return (nodeCount*thresholdPercent + 99) / 100
+}
+
 func (k *k8sClient) getReadyNodes() ([]corev1.Node, error) {
 	nodes, err := k.clientset.CoreV1().Nodes().List(context.TODO(), v1.ListOptions{})
 	if err != nil {
@@ -182,13 +209,13 @@ func getNodeReadyCondition(node *corev1.Node) *corev1.NodeCondition {
 	return nil
 }
 
-func (k *k8sClient) createAWSAuthConfigMap(nodeNameStrategy string, nodeRoleARN string) error {
+func (k *k8sClient) createAWSAuthConfigMap(ctx context.Context, nodeNameStrategy string, nodeRoleARN string) error {
 	mapRoles, err := generateAuthMapRole(nodeNameStrategy, nodeRoleARN)
 	if err != nil {
 		return err
 	}
 	klog.Infof("generated AuthMapRole %s", mapRoles)
-	_, err = k.clientset.CoreV1().ConfigMaps("kube-system").Create(context.TODO(), &corev1.ConfigMap{
+	_, err = k.clientset.CoreV1().ConfigMaps("kube-system").Create(ctx, &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "aws-auth",
 			Namespace: "kube-system",
@@ -197,7 +224,39 @@ func (k *k8sClient) createAWSAuthConfigMap(nodeNameStrategy string, nodeRoleARN
 			"mapRoles": mapRoles,
 		},
 	}, metav1.CreateOptions{})
-	return err
+	if err != nil {
+		return err
+	}
+	return k.waitForAWSAuthConfigMap(ctx, nodeRoleARN)
+}
+
+const (
+	awsAuthVerifyInterval = 2 * time.Second
+	awsAuthVerifyTimeout  = 1 * time.Minute
+)
+
+// waitForAWSAuthConfigMap reads back the aws-auth ConfigMap until the node role is mapped,
+// so nodes don't try to register before the API server can authorize them
+func (k *k8sClient) waitForAWSAuthConfigMap(ctx context.Context, nodeRoleARN string) error {
+	var configMap *corev1.ConfigMap
+	err := wait.PollUntilContextTimeout(ctx, awsAuthVerifyInterval, awsAuthVerifyTimeout, true, func(ctx context.Context) (bool, error) {
+		cm, err := k.clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "aws-auth", metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("failed to read back aws-auth ConfigMap: %v", err)
+			return false, nil
+		}
+		configMap = cm
+		if !strings.Contains(cm.Data["mapRoles"], nodeRoleARN) {
+			klog.Warningf("aws-auth ConfigMap does not map node role %s yet, retrying...", nodeRoleARN)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("aws-auth ConfigMap does not map node role %s: %v", nodeRoleARN, err)
+	}
+	klog.Infof("verified aws-auth ConfigMap: %v", configMap.Data)
+	return nil
 }
 
 func getNodeInstanceIDs(nodes []corev1.Node) ([]string, error) {
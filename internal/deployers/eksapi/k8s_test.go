@@ -0,0 +1,15 @@
+package eksapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readyNodeThreshold(t *testing.T) {
+	assert.Equal(t, 10, readyNodeThreshold(10, 100))
+	assert.Equal(t, 9, readyNodeThreshold(10, 90))
+	// rounds up
+	assert.Equal(t, 3, readyNodeThreshold(3, 90))
+	assert.Equal(t, 1, readyNodeThreshold(3, 1))
+}
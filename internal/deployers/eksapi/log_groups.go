@@ -0,0 +1,88 @@
+package eksapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"k8s.io/klog/v2"
+)
+
+// clusterLogGroupPrefixes are the prefixes of the CloudWatch log groups that EKS control plane logging and Container Insights create for a cluster
+func clusterLogGroupPrefixes(clusterName string) []string {
+	return []string{
+		fmt.Sprintf("/aws/eks/%s/", clusterName),
+		fmt.Sprintf("/aws/containerinsights/%s/", clusterName),
+	}
+}
+
+// getClusterTags returns the cluster's tags, which must be read before the cluster is deleted to match its log groups
+func getClusterTags(ctx context.Context, clients *awsClients, clusterName string) (map[string]string, error) {
+	out, err := clients.EKS().DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(clusterName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %v", err)
+	}
+	return out.Cluster.Tags, nil
+}
+
+// hasClusterTags reports whether a log group's tags include each of the cluster's tags
+func hasClusterTags(logGroupTags, clusterTags map[string]string) bool {
+	for key, value := range clusterTags {
+		if logGroupValue, ok := logGroupTags[key]; !ok || logGroupValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteClusterLogGroups deletes the cluster's CloudWatch log groups, which outlive the cluster and keep accruing storage costs.
+// A log group is the cluster's if it's named with one of the cluster's prefixes and has each of the cluster's tags.
+func deleteClusterLogGroups(ctx context.Context, clients *awsClients, clusterName string, clusterTags map[string]string) error {
+	var errs []error
+	for _, prefix := range clusterLogGroupPrefixes(clusterName) {
+		paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(clients.CloudWatchLogs(), &cloudwatchlogs.DescribeLogGroupsInput{
+			LogGroupNamePrefix: aws.String(prefix),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to describe log groups with prefix %s: %v", prefix, err))
+				break
+			}
+			for _, logGroup := range page.LogGroups {
+				name := aws.ToString(logGroup.LogGroupName)
+				if len(clusterTags) > 0 {
+					tags, err := clients.CloudWatchLogs().ListTagsForResource(ctx, &cloudwatchlogs.ListTagsForResourceInput{
+						ResourceArn: logGroup.LogGroupArn,
+					})
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to list tags of log group %s: %v", name, err))
+						continue
+					}
+					if !hasClusterTags(tags.Tags, clusterTags) {
+						klog.Infof("not deleting log group %s, it doesn't have the cluster's tags", name)
+						continue
+					}
+				}
+				_, err := clients.CloudWatchLogs().DeleteLogGroup(ctx, &cloudwatchlogs.DeleteLogGroupInput{
+					LogGroupName: aws.String(name),
+				})
+				if err != nil {
+					var notFound *cloudwatchlogstypes.ResourceNotFoundException
+					if !errors.As(err, &notFound) {
+						errs = append(errs, fmt.Errorf("failed to delete log group %s: %v", name, err))
+					}
+					continue
+				}
+				klog.Infof("deleted log group: %s", name)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
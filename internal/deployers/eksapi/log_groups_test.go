@@ -0,0 +1,19 @@
+package eksapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_clusterLogGroupPrefixes(t *testing.T) {
+	assert.Equal(t, []string{"/aws/eks/kubetest2-eksapi-abc/", "/aws/containerinsights/kubetest2-eksapi-abc/"}, clusterLogGroupPrefixes("kubetest2-eksapi-abc"))
+}
+
+func Test_hasClusterTags(t *testing.T) {
+	clusterTags := map[string]string{"owner": "team-a"}
+	assert.True(t, hasClusterTags(map[string]string{"owner": "team-a", "extra": "x"}, clusterTags))
+	assert.False(t, hasClusterTags(map[string]string{"owner": "team-b"}, clusterTags))
+	assert.False(t, hasClusterTags(nil, clusterTags))
+	assert.True(t, hasClusterTags(nil, nil))
+}
@@ -0,0 +1,153 @@
+package eksapi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"k8s.io/klog/v2"
+)
+
+const mutationLogFileName = "aws-mutations.jsonl"
+
+// readOnlyActionPrefixes are the prefixes of AWS API actions that don't change anything
+var readOnlyActionPrefixes = []string{"Describe", "List", "Get", "Head", "Lookup", "Search", "Select", "BatchGet"}
+
+// mutationResourceIDFields are the input fields that identify the resource of a mutating call, in order of preference
+var mutationResourceIDFields = []string{
+	// a cluster's sub-resources before the cluster, since their inputs also name the cluster
+	"NodegroupName",
+	"AddonName",
+	"ClusterName",
+	"StackName",
+	"RoleName",
+	"InstanceProfileName",
+	"LogGroupName",
+	"Name",
+	"Key",
+	"InstanceIds",
+	"NetworkInterfaceId",
+	"GroupId",
+	"ResourceArn",
+}
+
+// mutationEvent is one line of the mutation log
+type mutationEvent struct {
+	Time       time.Time `json:"time"`
+	Service    string    `json:"service"`
+	Action     string    `json:"action"`
+	ResourceID string    `json:"resourceID,omitempty"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// mutationLog appends an event to a JSONL file for each mutating AWS API call made through an AWS SDK config it's added to,
+// as a record of what the deployer changed
+type mutationLog struct {
+	path string
+	lock sync.Mutex
+}
+
+func newMutationLog(path string) *mutationLog {
+	return &mutationLog{path: path}
+}
+
+// addMiddleware is an AWS SDK API option that records the mutating calls of a client
+func (l *mutationLog) addMiddleware(stack *middleware.Stack) error {
+	// the operation's service and action are only in the context after the SDK's metadata middleware, so this goes last
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("MutationLog", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		action := awsmiddleware.GetOperationName(ctx)
+		if isMutatingAction(action) {
+			event := mutationEvent{
+				Time:       time.Now().UTC(),
+				Service:    awsmiddleware.GetServiceID(ctx),
+				Action:     action,
+				ResourceID: mutationResourceID(in.Parameters),
+				Result:     "success",
+			}
+			if err != nil {
+				event.Result = "error"
+				event.Error = err.Error()
+			}
+			l.append(event)
+		}
+		return out, metadata, err
+	}), middleware.After)
+}
+
+// append writes the event to the log. Failures are logged, so recording a call never fails it.
+func (l *mutationLog) append(event mutationEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		klog.Warningf("failed to marshal AWS mutation event: %v", err)
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		klog.Warningf("failed to create directory for AWS mutation log: %v", err)
+		return
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		klog.Warningf("failed to open AWS mutation log: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		klog.Warningf("failed to write AWS mutation log: %v", err)
+	}
+}
+
+func isMutatingAction(action string) bool {
+	if action == "" {
+		return false
+	}
+	for _, prefix := range readOnlyActionPrefixes {
+		if strings.HasPrefix(action, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// mutationResourceID returns the first of the mutationResourceIDFields set in the call's input, if any
+func mutationResourceID(input any) string {
+	v := reflect.ValueOf(input)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	for _, name := range mutationResourceIDFields {
+		field := v.FieldByName(name)
+		if !field.IsValid() {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Pointer && field.Elem().Kind() == reflect.String:
+			return field.Elem().String()
+		case field.Kind() == reflect.String && field.String() != "":
+			return field.String()
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String && field.Len() > 0:
+			var values []string
+			for i := 0; i < field.Len(); i++ {
+				values = append(values, field.Index(i).String())
+			}
+			return strings.Join(values, ",")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package eksapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeHTTPClient struct{}
+
+func (fakeHTTPClient) Do(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
+func Test_mutationLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifacts", mutationLogFileName)
+	mutationLog := newMutationLog(path)
+	client := eks.New(eks.Options{
+		Region:      "us-west-2",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  fakeHTTPClient{},
+		APIOptions:  []func(*middleware.Stack) error{mutationLog.addMiddleware},
+	})
+	_, err := client.DescribeCluster(context.TODO(), &eks.DescribeClusterInput{Name: aws.String("test")})
+	assert.NoError(t, err)
+	_, err = client.CreateAddon(context.TODO(), &eks.CreateAddonInput{ClusterName: aws.String("test"), AddonName: aws.String("vpc-cni")})
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 1)
+	var event mutationEvent
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, "EKS", event.Service)
+	assert.Equal(t, "CreateAddon", event.Action)
+	assert.Equal(t, "vpc-cni", event.ResourceID)
+	assert.Equal(t, "success", event.Result)
+}
+
+func Test_isMutatingAction(t *testing.T) {
+	assert.True(t, isMutatingAction("CreateStack"))
+	assert.True(t, isMutatingAction("PutObject"))
+	assert.False(t, isMutatingAction("DescribeStacks"))
+	assert.False(t, isMutatingAction("ListAddons"))
+	assert.False(t, isMutatingAction(""))
+}
+
+func Test_mutationResourceID(t *testing.T) {
+	assert.Equal(t, "role", mutationResourceID(&iam.DeleteRoleInput{RoleName: aws.String("role")}))
+	assert.Equal(t, "test", mutationResourceID(&eks.CreateClusterInput{Name: aws.String("test")}))
+	assert.Equal(t, "nodes", mutationResourceID(&eks.DeleteNodegroupInput{ClusterName: aws.String("test"), NodegroupName: aws.String("nodes")}))
+	assert.Equal(t, "", mutationResourceID(nil))
+}
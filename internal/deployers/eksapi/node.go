@@ -79,27 +79,30 @@ func NewNodeManager(clients *awsClients, resourceID string) *nodeManager {
 	}
 }
 
-func (m *nodeManager) createNodes(infra *Infrastructure, cluster *Cluster, opts *deployerOptions, k8sClient *k8sClient) error {
-	if err := m.resolveInstanceTypes(opts); err != nil {
+func (m *nodeManager) createNodes(ctx context.Context, infra *Infrastructure, cluster *Cluster, opts *deployerOptions, k8sClient *k8sClient) error {
+	if err := m.resolveInstanceTypes(ctx, opts); err != nil {
 		return fmt.Errorf("failed to resolve instance types: %v", err)
 	}
 	if opts.AutoMode {
-		if err := m.createNodeClass(opts, k8sClient); err != nil {
+		if err := k8sClient.waitForCRDsEstablished(ctx, autoModeCRDs, crdEstablishedTimeout); err != nil {
 			return err
 		}
-		if err := m.createNodePool(opts, k8sClient); err != nil {
+		if err := m.createNodeClass(ctx, opts, k8sClient); err != nil {
 			return err
 		}
-		_, err := m.createPlaceholderDeployment(opts, k8sClient)
+		if err := m.createNodePool(ctx, opts, k8sClient); err != nil {
+			return err
+		}
+		_, err := m.createPlaceholderDeployment(ctx, opts, k8sClient)
 		return err
 	} else if opts.UnmanagedNodes {
-		return m.createUnmanagedNodegroup(infra, cluster, opts)
+		return m.createUnmanagedNodegroup(ctx, infra, cluster, opts)
 	} else {
-		return m.createManagedNodegroup(infra, cluster, opts)
+		return m.createManagedNodegroup(ctx, infra, cluster, opts)
 	}
 }
 
-func (m *nodeManager) resolveInstanceTypes(opts *deployerOptions) (err error) {
+func (m *nodeManager) resolveInstanceTypes(ctx context.Context, opts *deployerOptions) (err error) {
 	instanceTypes := opts.InstanceTypes
 	if len(instanceTypes) == 0 {
 		if len(opts.InstanceTypeArchs) > 0 {
@@ -122,7 +125,7 @@ func (m *nodeManager) resolveInstanceTypes(opts *deployerOptions) (err error) {
 			}
 		} else if opts.UnmanagedNodes {
 			klog.Infof("choosing instance types based on AMI architecture...")
-			if out, err := m.clients.EC2().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{
+			if out, err := m.clients.EC2().DescribeImages(ctx, &ec2.DescribeImagesInput{
 				ImageIds: []string{opts.AMI},
 			}); err != nil {
 				return fmt.Errorf("failed to describe AMI: %s: %v", opts.AMI, err)
@@ -144,7 +147,7 @@ func (m *nodeManager) resolveInstanceTypes(opts *deployerOptions) (err error) {
 			instanceTypes = instanceTypesForAMIType
 		}
 	}
-	validInstanceTypes, err := m.getValidInstanceTypes(instanceTypes)
+	validInstanceTypes, err := m.getValidInstanceTypes(ctx, instanceTypes)
 	if err != nil {
 		return err
 	}
@@ -156,8 +159,8 @@ func (m *nodeManager) resolveInstanceTypes(opts *deployerOptions) (err error) {
 	return nil
 }
 
-func (m *nodeManager) createNodeClass(opts *deployerOptions, k8sClient *k8sClient) error {
-	nodeclass, err := k8sClient.dclient.Resource(nodeClassResource).Get(context.Background(), "default", metav1.GetOptions{})
+func (m *nodeManager) createNodeClass(ctx context.Context, opts *deployerOptions, k8sClient *k8sClient) error {
+	nodeclass, err := k8sClient.dclient.Resource(nodeClassResource).Get(ctx, "default", metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("getting default nodeclass, %w", err)
 	}
@@ -178,7 +181,7 @@ func (m *nodeManager) createNodeClass(opts *deployerOptions, k8sClient *k8sClien
 	}
 
 	klog.Infof("creating new node class...")
-	_, err = k8sClient.dclient.Resource(nodeClassResource).Create(context.Background(), nodeclass, metav1.CreateOptions{})
+	_, err = k8sClient.dclient.Resource(nodeClassResource).Create(ctx, nodeclass, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("creating new nodeclass, %w", err)
 	}
@@ -186,7 +189,7 @@ func (m *nodeManager) createNodeClass(opts *deployerOptions, k8sClient *k8sClien
 	return nil
 }
 
-func (m *nodeManager) createNodePool(opts *deployerOptions, k8sClient *k8sClient) error {
+func (m *nodeManager) createNodePool(ctx context.Context, opts *deployerOptions, k8sClient *k8sClient) error {
 	nodePool := karpv1.NodePool{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: m.resourceID,
@@ -238,16 +241,16 @@ func (m *nodeManager) createNodePool(opts *deployerOptions, k8sClient *k8sClient
 		},
 	}
 	klog.Infof("creating node pool...")
-	if err := k8sClient.client.Create(context.TODO(), &nodePool); err != nil {
+	if err := k8sClient.client.Create(ctx, &nodePool); err != nil {
 		return fmt.Errorf("failed to create node pool: %v", err)
 	}
 	klog.Infof("created node pool: %+v", nodePool)
 	return nil
 }
 
-func (m *nodeManager) deleteNodeClass(k8sClient *k8sClient) error {
+func (m *nodeManager) deleteNodeClass(ctx context.Context, k8sClient *k8sClient) error {
 	klog.Infof("deleting node class...")
-	if err := k8sClient.dclient.Resource(nodeClassResource).Delete(context.Background(), m.resourceID, metav1.DeleteOptions{}); err != nil {
+	if err := k8sClient.dclient.Resource(nodeClassResource).Delete(ctx, m.resourceID, metav1.DeleteOptions{}); err != nil {
 		if apierrors.IsNotFound(err) {
 			klog.Infof("node class does not exist: %s", m.resourceID)
 			return nil
@@ -258,14 +261,14 @@ func (m *nodeManager) deleteNodeClass(k8sClient *k8sClient) error {
 	return nil
 }
 
-func (m *nodeManager) deleteNodePool(k8sClient *k8sClient) error {
+func (m *nodeManager) deleteNodePool(ctx context.Context, k8sClient *k8sClient) error {
 	nodePool := karpv1.NodePool{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: m.resourceID,
 		},
 	}
 	klog.Infof("deleting node pool...")
-	if err := k8sClient.client.Delete(context.TODO(), &nodePool); err != nil {
+	if err := k8sClient.client.Delete(ctx, &nodePool); err != nil {
 		if apierrors.IsNotFound(err) {
 			klog.Infof("node pool does not exist: %s", m.resourceID)
 			return nil
@@ -279,7 +282,7 @@ func (m *nodeManager) deleteNodePool(k8sClient *k8sClient) error {
 // createPlaceholderDeployment creates a Deployment with the specified number of replicas that requires
 // each replica to be scheduled on different nodes.
 // This ensures that (at least) the specified number of nodes exist in an EKS Auto cluster
-func (m *nodeManager) createPlaceholderDeployment(opts *deployerOptions, k8sClient *k8sClient) (*appsv1.Deployment, error) {
+func (m *nodeManager) createPlaceholderDeployment(ctx context.Context, opts *deployerOptions, k8sClient *k8sClient) (*appsv1.Deployment, error) {
 	if opts.Nodes == 0 {
 		klog.Info("not creating placeholder deployment!")
 		return nil, nil
@@ -322,8 +325,11 @@ func (m *nodeManager) createPlaceholderDeployment(opts *deployerOptions, k8sClie
 			},
 		},
 	}
+	if err := stampPodMetadata(&d.Spec.Template, opts); err != nil {
+		return nil, err
+	}
 	klog.Infof("creating placeholder deployment...")
-	d, err := k8sClient.clientset.AppsV1().Deployments("default").Create(context.TODO(), d, metav1.CreateOptions{})
+	d, err := k8sClient.clientset.AppsV1().Deployments("default").Create(ctx, d, metav1.CreateOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create placeholder deployment: %v", err)
 	}
@@ -331,9 +337,9 @@ func (m *nodeManager) createPlaceholderDeployment(opts *deployerOptions, k8sClie
 	return d, nil
 }
 
-func (m *nodeManager) deletePlaceholderDeployment(k8sClient *k8sClient) error {
+func (m *nodeManager) deletePlaceholderDeployment(ctx context.Context, k8sClient *k8sClient) error {
 	klog.Infof("deleting placeholder deployment...")
-	if err := k8sClient.clientset.AppsV1().Deployments("default").Delete(context.TODO(), m.resourceID, *metav1.NewDeleteOptions( /* no grace period */ 0)); err != nil {
+	if err := k8sClient.clientset.AppsV1().Deployments("default").Delete(ctx, m.resourceID, *metav1.NewDeleteOptions( /* no grace period */ 0)); err != nil {
 		if apierrors.IsNotFound(err) {
 			klog.Infof("placeholder deployment does not exist: %s", m.resourceID)
 			return nil
@@ -344,7 +350,7 @@ func (m *nodeManager) deletePlaceholderDeployment(k8sClient *k8sClient) error {
 	return nil
 }
 
-func (m *nodeManager) createManagedNodegroup(infra *Infrastructure, cluster *Cluster, opts *deployerOptions) error {
+func (m *nodeManager) createManagedNodegroup(ctx context.Context, infra *Infrastructure, cluster *Cluster, opts *deployerOptions) error {
 	klog.Infof("creating nodegroup...")
 	input := eks.CreateNodegroupInput{
 		ClusterName:   aws.String(m.resourceID),
@@ -361,13 +367,13 @@ func (m *nodeManager) createManagedNodegroup(infra *Infrastructure, cluster *Clu
 		AmiType:       ekstypes.AMITypes(opts.AMIType),
 		InstanceTypes: opts.InstanceTypes,
 	}
-	out, err := m.clients.EKS().CreateNodegroup(context.TODO(), &input)
+	out, err := m.clients.EKS().CreateNodegroup(ctx, &input)
 	if err != nil {
 		return err
 	}
 	klog.Infof("waiting for nodegroup to be active: %s", *out.Nodegroup.NodegroupArn)
 	err = eks.NewNodegroupActiveWaiter(m.clients.EKS()).
-		Wait(context.TODO(), &eks.DescribeNodegroupInput{
+		Wait(ctx, &eks.DescribeNodegroupInput{
 			ClusterName:   input.ClusterName,
 			NodegroupName: input.NodegroupName,
 		}, opts.NodeCreationTimeout)
@@ -376,7 +382,7 @@ func (m *nodeManager) createManagedNodegroup(infra *Infrastructure, cluster *Clu
 	}
 	klog.Infof("nodegroup is active: %s", *out.Nodegroup.NodegroupArn)
 	if opts.ExpectedAMI != "" {
-		out, err := m.clients.EKS().DescribeNodegroup(context.TODO(), &eks.DescribeNodegroupInput{
+		out, err := m.clients.EKS().DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
 			ClusterName:   input.ClusterName,
 			NodegroupName: input.NodegroupName,
 		})
@@ -384,7 +390,7 @@ func (m *nodeManager) createManagedNodegroup(infra *Infrastructure, cluster *Clu
 			return err
 		}
 		asgName := out.Nodegroup.Resources.AutoScalingGroups[0].Name
-		if ok, err := m.verifyASGAMI(*asgName, opts.ExpectedAMI); err != nil {
+		if ok, err := m.verifyASGAMI(ctx, *asgName, opts.ExpectedAMI); err != nil {
 			return err
 		} else if !ok {
 			return fmt.Errorf("ASG %s is not using expected AMI: %s", *asgName, opts.ExpectedAMI)
@@ -393,7 +399,7 @@ func (m *nodeManager) createManagedNodegroup(infra *Infrastructure, cluster *Clu
 	return nil
 }
 
-func (m *nodeManager) createUnmanagedNodegroup(infra *Infrastructure, cluster *Cluster, opts *deployerOptions) error {
+func (m *nodeManager) createUnmanagedNodegroup(ctx context.Context, infra *Infrastructure, cluster *Cluster, opts *deployerOptions) error {
 	var availabilityZoneFilter []string
 	var capacityReservationId string
 	stackName := m.getUnmanagedNodegroupStackName()
@@ -403,23 +409,23 @@ func (m *nodeManager) createUnmanagedNodegroup(infra *Infrastructure, cluster *C
 		return err
 	}
 	if opts.CapacityReservation {
-		capacityReservation, err := m.getCapacityReservation(opts)
+		capacityReservation, err := m.getCapacityReservation(ctx, opts)
 		if err != nil {
 			return err
 		}
 		capacityReservationId = aws.ToString(capacityReservation.CapacityReservationId)
 		availabilityZoneFilter = []string{aws.ToString(capacityReservation.AvailabilityZone)}
 	} else {
-		availabilityZoneFilter, err = m.getValidAvailabilityZonesFilter(opts, infra)
+		availabilityZoneFilter, err = m.getValidAvailabilityZonesFilter(ctx, opts, infra)
 		if err != nil {
 			return err
 		}
 	}
-	targetSubnets, err := m.getValidSubnets(opts, infra, availabilityZoneFilter)
+	targetSubnets, err := m.getValidSubnets(ctx, opts, infra, availabilityZoneFilter)
 	if err != nil {
 		return err
 	}
-	networkInterfaces, err := m.getNetworkInterfaces(opts, []string{cluster.securityGroupId}, targetSubnets)
+	networkInterfaces, err := m.getNetworkInterfaces(ctx, opts, []string{cluster.securityGroupId}, targetSubnets)
 	if err != nil {
 		return err
 	}
@@ -493,13 +499,13 @@ func (m *nodeManager) createUnmanagedNodegroup(infra *Infrastructure, cluster *C
 			},
 		},
 	}
-	out, err := m.clients.CFN().CreateStack(context.TODO(), &input)
+	out, err := m.clients.CFN().CreateStack(ctx, &input)
 	if err != nil {
 		return err
 	}
 	klog.Infof("waiting for unmanaged nodegroup stack to be created: %s", aws.ToString(out.StackId))
 	err = cloudformation.NewStackCreateCompleteWaiter(m.clients.CFN()).
-		Wait(context.TODO(),
+		Wait(ctx,
 			&cloudformation.DescribeStacksInput{
 				StackName: out.StackId,
 			},
@@ -509,7 +515,7 @@ func (m *nodeManager) createUnmanagedNodegroup(infra *Infrastructure, cluster *C
 	}
 	klog.Infof("created unmanaged nodegroup stack: %s", *out.StackId)
 	if opts.ExpectedAMI != "" {
-		if ok, err := m.verifyASGAMI(m.resourceID, opts.ExpectedAMI); err != nil {
+		if ok, err := m.verifyASGAMI(ctx, m.resourceID, opts.ExpectedAMI); err != nil {
 			return err
 		} else if !ok {
 			return fmt.Errorf("ASG %s is not using expected AMI: %s", m.resourceID, opts.ExpectedAMI)
@@ -521,39 +527,39 @@ func (m *nodeManager) createUnmanagedNodegroup(infra *Infrastructure, cluster *C
 // deleteNodes cleans up any nodes in the cluster
 // it will be called outside the context of a deployer run (by the janitor, for example)
 // so will try to delete nodes of any type
-func (m *nodeManager) deleteNodes(k8sClient *k8sClient, opts *deployerOptions) error {
-	if err := m.deleteUnmanagedNodegroup(); err != nil {
+func (m *nodeManager) deleteNodes(ctx context.Context, k8sClient *k8sClient, opts *deployerOptions) error {
+	if err := m.deleteUnmanagedNodegroup(ctx); err != nil {
 		return err
 	}
-	if err := m.deleteManagedNodegroup(); err != nil {
+	if err := m.deleteManagedNodegroup(ctx); err != nil {
 		return err
 	}
 	// we only have a k8sClient when this is called by the deployer, not by the janitor
 	// TODO implement cleanup of Auto nodes in the janitor
 	if k8sClient != nil && opts != nil && opts.AutoMode {
-		if err := m.deletePlaceholderDeployment(k8sClient); err != nil {
+		if err := m.deletePlaceholderDeployment(ctx, k8sClient); err != nil {
 			return err
 		}
-		if err := m.deleteNodeClass(k8sClient); err != nil {
+		if err := m.deleteNodeClass(ctx, k8sClient); err != nil {
 			return err
 		}
-		if err := m.deleteNodePool(k8sClient); err != nil {
+		if err := m.deleteNodePool(ctx, k8sClient); err != nil {
 			return err
 		}
-		if err := k8sClient.waitForNodeDeletion(nodeDeletionTimeout); err != nil {
+		if err := k8sClient.waitForNodeDeletion(ctx, nodeDeletionTimeout); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (m *nodeManager) deleteManagedNodegroup() error {
+func (m *nodeManager) deleteManagedNodegroup(ctx context.Context) error {
 	input := eks.DeleteNodegroupInput{
 		ClusterName:   aws.String(m.resourceID),
 		NodegroupName: aws.String(m.resourceID),
 	}
 	klog.Infof("deleting nodegroup...")
-	out, err := m.clients.EKS().DeleteNodegroup(context.TODO(), &input)
+	out, err := m.clients.EKS().DeleteNodegroup(ctx, &input)
 	if err != nil {
 		var notFound *ekstypes.ResourceNotFoundException
 		if errors.As(err, &notFound) {
@@ -564,7 +570,7 @@ func (m *nodeManager) deleteManagedNodegroup() error {
 	}
 	klog.Infof("waiting for nodegroup deletion: %s", *out.Nodegroup.NodegroupArn)
 	err = eks.NewNodegroupDeletedWaiter(m.clients.EKS()).
-		Wait(context.TODO(), &eks.DescribeNodegroupInput{
+		Wait(ctx, &eks.DescribeNodegroupInput{
 			ClusterName:   input.ClusterName,
 			NodegroupName: input.NodegroupName,
 		}, nodeDeletionTimeout)
@@ -575,13 +581,13 @@ func (m *nodeManager) deleteManagedNodegroup() error {
 	return nil
 }
 
-func (m *nodeManager) deleteUnmanagedNodegroup() error {
+func (m *nodeManager) deleteUnmanagedNodegroup(ctx context.Context) error {
 	stackName := m.getUnmanagedNodegroupStackName()
 	input := cloudformation.DeleteStackInput{
 		StackName: aws.String(stackName),
 	}
 	klog.Infof("deleting unmanaged nodegroup stack: %s", stackName)
-	_, err := m.clients.CFN().DeleteStack(context.TODO(), &input)
+	_, err := m.clients.CFN().DeleteStack(ctx, &input)
 	if err != nil {
 		var notFound *cloudformationtypes.StackNotFoundException
 		if errors.As(err, &notFound) {
@@ -592,7 +598,7 @@ func (m *nodeManager) deleteUnmanagedNodegroup() error {
 	}
 	klog.Infof("waiting for unmanaged nodegroup stack to be deleted: %s", stackName)
 	err = cloudformation.NewStackDeleteCompleteWaiter(m.clients.CFN()).
-		Wait(context.TODO(),
+		Wait(ctx,
 			&cloudformation.DescribeStacksInput{
 				StackName: aws.String(stackName),
 			},
@@ -605,12 +611,16 @@ func (m *nodeManager) deleteUnmanagedNodegroup() error {
 }
 
 func (m *nodeManager) getUnmanagedNodegroupStackName() string {
-	return fmt.Sprintf("%s-unmanaged-nodegroup", m.resourceID)
+	return getUnmanagedNodegroupStackName(m.resourceID)
+}
+
+func getUnmanagedNodegroupStackName(resourceID string) string {
+	return fmt.Sprintf("%s-unmanaged-nodegroup", resourceID)
 }
 
-func (m *nodeManager) verifyASGAMI(asgName string, amiId string) (bool, error) {
+func (m *nodeManager) verifyASGAMI(ctx context.Context, asgName string, amiId string) (bool, error) {
 	klog.Infof("verifying AMI is %s for ASG: %s", amiId, asgName)
-	asgOut, err := m.clients.ASG().DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{
+	asgOut, err := m.clients.ASG().DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
 		AutoScalingGroupNames: []string{asgName},
 	})
 	if err != nil {
@@ -624,7 +634,7 @@ func (m *nodeManager) verifyASGAMI(asgName string, amiId string) (bool, error) {
 		instanceIds = append(instanceIds, *instance.InstanceId)
 	}
 	klog.Infof("verifying AMI for instances: %v", instanceIds)
-	ec2Out, err := m.clients.EC2().DescribeInstances(context.TODO(), &ec2.DescribeInstancesInput{
+	ec2Out, err := m.clients.EC2().DescribeInstances(ctx, &ec2.DescribeInstancesInput{
 		InstanceIds: instanceIds,
 	})
 	if err != nil {
@@ -645,7 +655,7 @@ func (m *nodeManager) verifyASGAMI(asgName string, amiId string) (bool, error) {
 	return true, nil
 }
 
-func (m *nodeManager) getCapacityReservation(opts *deployerOptions) (*ec2types.CapacityReservation, error) {
+func (m *nodeManager) getCapacityReservation(ctx context.Context, opts *deployerOptions) (*ec2types.CapacityReservation, error) {
 	describeReservationsInput := ec2.DescribeCapacityReservationsInput{
 		Filters: []ec2types.Filter{
 			{
@@ -661,7 +671,7 @@ func (m *nodeManager) getCapacityReservation(opts *deployerOptions) (*ec2types.C
 	if opts.TargetCapacityReservationId != "" {
 		describeReservationsInput.CapacityReservationIds = []string{opts.TargetCapacityReservationId}
 	}
-	capacityReservations, err := m.clients.EC2().DescribeCapacityReservations(context.TODO(), &describeReservationsInput)
+	capacityReservations, err := m.clients.EC2().DescribeCapacityReservations(ctx, &describeReservationsInput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe capacity reservation: %v", err)
 	}
@@ -679,7 +689,7 @@ func (m *nodeManager) getCapacityReservation(opts *deployerOptions) (*ec2types.C
 	return capacityReservation, nil
 }
 
-func (m *nodeManager) getValidAvailabilityZonesFilter(opts *deployerOptions, infra *Infrastructure) ([]string, error) {
+func (m *nodeManager) getValidAvailabilityZonesFilter(ctx context.Context, opts *deployerOptions, infra *Infrastructure) ([]string, error) {
 	if !opts.EFA {
 		// no filter needed, leaves scheduling to EC2 provisioner
 		return []string{}, nil
@@ -694,7 +704,7 @@ func (m *nodeManager) getValidAvailabilityZonesFilter(opts *deployerOptions, inf
 			Values: infra.availabilityZones,
 		},
 	}
-	describeResponse, err := m.clients.EC2().DescribeInstanceTypeOfferings(context.TODO(), &ec2.DescribeInstanceTypeOfferingsInput{
+	describeResponse, err := m.clients.EC2().DescribeInstanceTypeOfferings(ctx, &ec2.DescribeInstanceTypeOfferingsInput{
 		Filters:      describeFilters,
 		LocationType: ec2types.LocationTypeAvailabilityZone,
 	})
@@ -714,7 +724,7 @@ func (m *nodeManager) getValidAvailabilityZonesFilter(opts *deployerOptions, inf
 	return []string{targetAZ}, nil
 }
 
-func (m *nodeManager) getValidSubnets(opts *deployerOptions, infra *Infrastructure, availabilityZoneFilter []string) ([]string, error) {
+func (m *nodeManager) getValidSubnets(ctx context.Context, opts *deployerOptions, infra *Infrastructure, availabilityZoneFilter []string) ([]string, error) {
 	var describeFilters []ec2types.Filter
 	var targetSubnets []string
 	if opts.EFA {
@@ -729,7 +739,7 @@ func (m *nodeManager) getValidSubnets(opts *deployerOptions, infra *Infrastructu
 			Values: availabilityZoneFilter,
 		})
 	}
-	describeResponse, err := m.clients.EC2().DescribeSubnets(context.TODO(), &ec2.DescribeSubnetsInput{
+	describeResponse, err := m.clients.EC2().DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
 		Filters:   describeFilters,
 		SubnetIds: targetSubnets,
 	})
@@ -747,11 +757,11 @@ func (m *nodeManager) getValidSubnets(opts *deployerOptions, infra *Infrastructu
 	return subnetIds, nil
 }
 
-func (m *nodeManager) getValidInstanceTypes(desiredInstanceTypes []string) ([]string, error) {
+func (m *nodeManager) getValidInstanceTypes(ctx context.Context, desiredInstanceTypes []string) ([]string, error) {
 	var validInstanceTypes []string
 	for _, instanceType := range desiredInstanceTypes {
 		ec2InstanceType := ec2types.InstanceType(instanceType)
-		_, err := m.clients.EC2().DescribeInstanceTypes(context.TODO(), &ec2.DescribeInstanceTypesInput{
+		_, err := m.clients.EC2().DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
 			InstanceTypes: []ec2types.InstanceType{ec2InstanceType},
 		})
 		if err != nil {
@@ -768,7 +778,7 @@ func (m *nodeManager) getValidInstanceTypes(desiredInstanceTypes []string) ([]st
 	return validInstanceTypes, nil
 }
 
-func (m *nodeManager) getNetworkInterfaces(opts *deployerOptions, securityGroups []string, subnetIDs []string) ([]templates.NetworkInterface, error) {
+func (m *nodeManager) getNetworkInterfaces(ctx context.Context, opts *deployerOptions, securityGroups []string, subnetIDs []string) ([]templates.NetworkInterface, error) {
 	if !opts.EFA {
 		// create only the default primary network interface if not using EFA
 		netiface, err := getNetworkInterface(opts, 0, subnetIDs, securityGroups)
@@ -780,7 +790,7 @@ func (m *nodeManager) getNetworkInterfaces(opts *deployerOptions, securityGroups
 	// EFA option assumes a single instance type
 	instanceType := opts.InstanceTypes[0]
 	ec2InstanceType := ec2types.InstanceType(instanceType)
-	describeInstanceTypeOutput, err := m.clients.EC2().DescribeInstanceTypes(context.TODO(), &ec2.DescribeInstanceTypesInput{
+	describeInstanceTypeOutput, err := m.clients.EC2().DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
 		InstanceTypes: []ec2types.InstanceType{ec2InstanceType},
 	})
 	if err != nil {
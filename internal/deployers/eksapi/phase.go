@@ -0,0 +1,55 @@
+package eksapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// phaseDeadline enforces an overall timeout on a multi-step operation like Up or Down.
+// Its context is passed to each step's AWS waiters and Kubernetes polls, so a step that hangs is aborted once the timeout elapses,
+// and the phase in progress is recorded so the timeout error can name it.
+type phaseDeadline struct {
+	operation string
+	timeout   time.Duration
+	phase     string
+}
+
+// newPhaseDeadline returns a phaseDeadline for the operation, and the context that enforces it. A zero timeout never expires.
+func newPhaseDeadline(parent context.Context, operation string, timeout time.Duration) (*phaseDeadline, context.Context, context.CancelFunc) {
+	p := &phaseDeadline{
+		operation: operation,
+		timeout:   timeout,
+	}
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(parent)
+		return p, ctx, cancel
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return p, ctx, cancel
+}
+
+// enter records the start of the next phase, or returns an error if ctx is already done, e.g. because a step ignored it.
+// A nil phaseDeadline only checks ctx.
+func (p *phaseDeadline) enter(ctx context.Context, phase string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not starting phase %q: %w", phase, err)
+	}
+	if p == nil {
+		return nil
+	}
+	klog.Infof("%s: %s", p.operation, phase)
+	p.phase = phase
+	return nil
+}
+
+// err returns a timeout error naming the phase in progress if ctx's deadline caused err, otherwise err as is
+func (p *phaseDeadline) err(ctx context.Context, err error) error {
+	if p == nil || err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%s timed out after %v during phase %q: %w", p.operation, p.timeout, p.phase, err)
+}
@@ -0,0 +1,40 @@
+package eksapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_phaseDeadline(t *testing.T) {
+	var nilDeadline *phaseDeadline
+	assert.NoError(t, nilDeadline.enter(context.Background(), "cluster"))
+	assert.EqualError(t, nilDeadline.err(context.Background(), errors.New("failed")), "failed")
+
+	noTimeout, ctx, cancel := newPhaseDeadline(context.Background(), "Up", 0)
+	defer cancel()
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+	assert.NoError(t, noTimeout.enter(ctx, "cluster"))
+
+	deadline, ctx, cancel := newPhaseDeadline(context.Background(), "Up", time.Millisecond)
+	defer cancel()
+	assert.NoError(t, deadline.enter(ctx, "cluster"))
+	<-ctx.Done()
+	// a step that was aborted by the deadline
+	assert.EqualError(t, deadline.err(ctx, errors.New("failed to wait for cluster to become active: context deadline exceeded")),
+		`Up timed out after 1ms during phase "cluster": failed to wait for cluster to become active: context deadline exceeded`)
+	assert.EqualError(t, deadline.err(ctx, deadline.enter(ctx, "nodes")), `Up timed out after 1ms during phase "cluster": not starting phase "nodes": context deadline exceeded`)
+	assert.NoError(t, deadline.err(ctx, nil))
+}
+
+func Test_phaseDeadline_canceled(t *testing.T) {
+	deadline, ctx, cancel := newPhaseDeadline(context.Background(), "Down", time.Hour)
+	assert.NoError(t, deadline.enter(ctx, "nodes"))
+	cancel()
+	// only the deadline is reported as a timeout
+	assert.EqualError(t, deadline.err(ctx, errors.New("failed")), "failed")
+}
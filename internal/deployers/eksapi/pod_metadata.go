@@ -0,0 +1,70 @@
+package eksapi
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// stampPodMetadata merges the --pod-labels and --pod-annotations into a pod template.
+// Every pod created by the deployer should go through this, so in-cluster tooling can attribute them to a run.
+// Existing keys in the template win, since they may be used by selectors.
+func stampPodMetadata(template *corev1.PodTemplateSpec, opts *deployerOptions) error {
+	labels, err := parsePodLabels(opts.PodLabels)
+	if err != nil {
+		return err
+	}
+	annotations, err := parseKeyValuePairs(opts.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	template.Labels = mergeMissing(template.Labels, labels)
+	template.Annotations = mergeMissing(template.Annotations, annotations)
+	return nil
+}
+
+func parsePodLabels(labelPairs []string) (map[string]string, error) {
+	labels, err := parseKeyValuePairs(labelPairs)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid pod label key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid pod label value %q: %s", value, strings.Join(errs, ", "))
+		}
+	}
+	return labels, nil
+}
+
+// parseKeyValuePairs parses key=value pairs, the value may contain '='
+func parseKeyValuePairs(pairs []string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key=value pair: %s", pair)
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+func mergeMissing(dst map[string]string, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string)
+	}
+	for key, value := range src {
+		if _, ok := dst[key]; !ok {
+			dst[key] = value
+		}
+	}
+	return dst
+}
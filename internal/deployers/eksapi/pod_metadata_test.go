@@ -0,0 +1,31 @@
+package eksapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_stampPodMetadata(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "placeholder"},
+		},
+	}
+	opts := deployerOptions{
+		PodLabels:      []string{"team=node", "app=overridden"},
+		PodAnnotations: []string{"example.com/query=a=b"},
+	}
+	assert.NoError(t, stampPodMetadata(&template, &opts))
+	assert.Equal(t, map[string]string{"app": "placeholder", "team": "node"}, template.Labels)
+	assert.Equal(t, map[string]string{"example.com/query": "a=b"}, template.Annotations)
+}
+
+func Test_parsePodLabels(t *testing.T) {
+	_, err := parsePodLabels([]string{"team"})
+	assert.Error(t, err)
+	_, err = parsePodLabels([]string{"team=not a valid value"})
+	assert.Error(t, err)
+}
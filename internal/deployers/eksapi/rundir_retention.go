@@ -0,0 +1,117 @@
+package eksapi
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+// runDirEntry is a previous run's directory, next to the current run's directory
+type runDirEntry struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// verifyRunDirRetention checks the --rundir-retention-* flags and returns the size limit in bytes (zero if unset)
+func verifyRunDirRetention(maxAge time.Duration, maxSize string) (int64, error) {
+	if maxAge < 0 {
+		return 0, fmt.Errorf("--rundir-retention-max-age must not be negative")
+	}
+	if maxSize == "" {
+		return 0, nil
+	}
+	quantity, err := resource.ParseQuantity(maxSize)
+	if err != nil {
+		return 0, fmt.Errorf("--rundir-retention-max-size is invalid: %v", err)
+	}
+	if quantity.Sign() <= 0 {
+		return 0, fmt.Errorf("--rundir-retention-max-size must be positive")
+	}
+	return quantity.Value(), nil
+}
+
+// pruneRunDirs deletes the directories of previous runs next to currentRunDir,
+// first any older than maxAge, then the oldest remaining until they total at most maxSize bytes.
+// A zero maxAge or maxSize disables that limit. The current run's directory is never deleted.
+func pruneRunDirs(currentRunDir string, maxAge time.Duration, maxSize int64) error {
+	if maxAge == 0 && maxSize == 0 {
+		return nil
+	}
+	parent := filepath.Dir(currentRunDir)
+	entries, err := os.ReadDir(parent)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to list run directories in %s: %v", parent, err)
+	}
+	var runDirs []runDirEntry
+	for _, entry := range entries {
+		path := filepath.Join(parent, entry.Name())
+		if !entry.IsDir() || path == filepath.Clean(currentRunDir) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat run directory %s: %v", path, err)
+		}
+		size, err := dirSize(path)
+		if err != nil {
+			return fmt.Errorf("failed to measure run directory %s: %v", path, err)
+		}
+		runDirs = append(runDirs, runDirEntry{path: path, modTime: info.ModTime(), size: size})
+	}
+	slices.SortFunc(runDirs, func(a, b runDirEntry) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	var totalSize int64
+	for _, runDir := range runDirs {
+		totalSize += runDir.size
+	}
+	for _, runDir := range runDirs {
+		expired := maxAge > 0 && time.Since(runDir.modTime) > maxAge
+		oversized := maxSize > 0 && totalSize > maxSize
+		if !expired && !oversized {
+			continue
+		}
+		klog.Infof("deleting previous run directory %s (last modified %v, %d bytes)", runDir.path, runDir.modTime, runDir.size)
+		if err := os.RemoveAll(runDir.path); err != nil {
+			return fmt.Errorf("failed to delete run directory %s: %v", runDir.path, err)
+		}
+		totalSize -= runDir.size
+	}
+	return nil
+}
+
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// pruneRunDirs applies the --rundir-retention-* limits to previous runs' directories
+func (d *deployer) pruneRunDirs() error {
+	maxSize, err := verifyRunDirRetention(d.RunDirRetentionMaxAge, d.RunDirRetentionMaxSize)
+	if err != nil {
+		return err
+	}
+	return pruneRunDirs(d.commonOptions.RunDir(), d.RunDirRetentionMaxAge, maxSize)
+}
@@ -0,0 +1,45 @@
+package eksapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyRunDirRetention(t *testing.T) {
+	maxSize, err := verifyRunDirRetention(time.Hour, "1Ki")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), maxSize)
+
+	_, err = verifyRunDirRetention(-time.Hour, "")
+	assert.Error(t, err)
+	_, err = verifyRunDirRetention(0, "lots")
+	assert.Error(t, err)
+	_, err = verifyRunDirRetention(0, "0")
+	assert.Error(t, err)
+}
+
+func Test_pruneRunDirs(t *testing.T) {
+	parent := t.TempDir()
+	now := time.Now()
+	writeRunDir := func(name string, age time.Duration, size int) string {
+		path := filepath.Join(parent, name)
+		assert.NoError(t, os.MkdirAll(path, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, "artifact"), make([]byte, size), 0644))
+		assert.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+		return path
+	}
+	expired := writeRunDir("expired", 48*time.Hour, 10)
+	oldest := writeRunDir("oldest", 3*time.Hour, 100)
+	newer := writeRunDir("newer", 2*time.Hour, 100)
+	current := writeRunDir("current", 0, 1000)
+
+	assert.NoError(t, pruneRunDirs(current, 24*time.Hour, 150))
+	assert.NoDirExists(t, expired)
+	assert.NoDirExists(t, oldest)
+	assert.DirExists(t, newer)
+	assert.DirExists(t, current)
+}
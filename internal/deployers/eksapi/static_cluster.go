@@ -149,6 +149,9 @@ func (s *StaticClusterManager) DeployBusyboxAndWaitForNodes() error {
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal deployment: %v", err)
 	}
+	if err := stampPodMetadata(&deployment.Spec.Template, s.options); err != nil {
+		return err
+	}
 
 	result, err := s.k8sClient.AppsV1().Deployments("default").Create(context.TODO(), deployment, metav1.CreateOptions{})
 	if err != nil {
@@ -0,0 +1,300 @@
+package eksapi
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/internal/awssdk"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	supportBundleDir = "support-bundle"
+	// addonLogTailLines is how many lines of each add-on container's log are included in the support bundle
+	addonLogTailLines = 1000
+	// addonNamespace is where EKS runs the managed add-ons, along with the other cluster components
+	addonNamespace = "kube-system"
+	redacted       = "<redacted>"
+)
+
+// NewSupportBundleCollector returns a collector for the support bundle of the cluster created by the kubetest2-eksapi run with resourceID,
+// for use outside of the run, e.g. once it has failed. The Kubernetes objects and add-on logs are only collected with a kubeconfigPath.
+func NewSupportBundleCollector(resourceID string, clusterName string, kubeconfigPath string, eksEndpointURL string, logBucket string) *SupportBundleCollector {
+	if clusterName == "" {
+		clusterName = resourceID
+	}
+	return &SupportBundleCollector{
+		logManager: &logManager{
+			clients:    newAWSClients(awssdk.NewConfig(), eksEndpointURL),
+			resourceID: resourceID,
+		},
+		clusterName:    clusterName,
+		kubeconfigPath: kubeconfigPath,
+		logBucket:      logBucket,
+	}
+}
+
+// SupportBundleCollector gathers the support bundle of a kubetest2-eksapi cluster
+type SupportBundleCollector struct {
+	logManager     *logManager
+	clusterName    string
+	kubeconfigPath string
+	logBucket      string
+}
+
+// Collect writes the support bundle to outputDir, and uploads it to the log bucket if one is set
+func (c *SupportBundleCollector) Collect(ctx context.Context, outputDir string) error {
+	var k8sClient *k8sClient
+	if c.kubeconfigPath != "" {
+		client, err := newK8sClient(c.kubeconfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to create k8s client: %v", err)
+		}
+		k8sClient = client
+	}
+	return c.logManager.dumpSupportBundle(ctx, k8sClient, nil, c.clusterName, c.logBucket, outputDir)
+}
+
+// dumpSupportBundle gathers the cluster description, managed add-ons, CloudFormation stack events, core Kubernetes objects,
+// add-on logs, and the redacted deployer options into a tarball in outputDir, and uploads it to logBucket if one is set.
+// Each item is gathered independently, so the bundle contains whatever could be collected from a broken cluster.
+// Without opts, as when collected outside of the run, events are gathered for each of the stacks the deployer may have created.
+func (m *logManager) dumpSupportBundle(ctx context.Context, k8sClient *k8sClient /* nillable */, opts *deployerOptions /* nillable */, clusterName string, logBucket string, outputDir string) error {
+	var errs []error
+	files := make(map[string]any)
+
+	if opts != nil {
+		files["deployer-options.json"] = redactDeployerOptions(*opts)
+	}
+	if clusterName != "" {
+		if out, err := m.clients.EKS().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String(clusterName)}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to describe cluster: %v", err))
+		} else {
+			files["cluster.json"] = out.Cluster
+		}
+		if addons, err := m.describeAddons(ctx, clusterName); err != nil {
+			errs = append(errs, err)
+		} else {
+			files["addons.json"] = addons
+		}
+	}
+	for _, stackName := range m.supportBundleStackNames(opts) {
+		events, err := m.getStackEvents(ctx, stackName)
+		if err != nil {
+			// CloudFormation reports a stack that doesn't exist as a ValidationError
+			var apiErr smithy.APIError
+			if opts == nil && errors.As(err, &apiErr) && apiErr.ErrorCode() == "ValidationError" {
+				klog.Infof("stack %s does not exist, its events will not be included in the support bundle", stackName)
+				continue
+			}
+			errs = append(errs, fmt.Errorf("failed to get stack events for %s: %v", stackName, err))
+			continue
+		}
+		files[fmt.Sprintf("stack-events/%s.json", stackName)] = events
+	}
+	if k8sClient == nil {
+		klog.Infof("no k8s client available, Kubernetes objects and add-on logs will not be included in the support bundle!")
+	} else {
+		if nodes, err := k8sClient.clientset.CoreV1().Nodes().List(ctx, v1.ListOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to list nodes: %v", err))
+		} else {
+			files["nodes.json"] = nodes
+		}
+		if pods, err := k8sClient.clientset.CoreV1().Pods("").List(ctx, v1.ListOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to list pods: %v", err))
+		} else {
+			files["pods.json"] = pods
+		}
+		if events, err := k8sClient.clientset.CoreV1().Events("").List(ctx, v1.ListOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to list events: %v", err))
+		} else {
+			files["events.json"] = events
+		}
+		if err := addAddonLogs(ctx, k8sClient, files); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	bundle, err := writeSupportBundle(files)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to create support bundle directory: %v", err))...)
+	}
+	bundleName := fmt.Sprintf("%s.tar.gz", m.resourceID)
+	bundlePath := filepath.Join(outputDir, bundleName)
+	if err := os.WriteFile(bundlePath, bundle, 0644); err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to write support bundle: %v", err))...)
+	}
+	klog.Infof("wrote support bundle: %s", bundlePath)
+	if logBucket != "" {
+		key := fmt.Sprintf("%s/%s", supportBundleDir, bundleName)
+		if _, err := m.clients.S3().PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(logBucket),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader(bundle),
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to upload support bundle: %v", err))
+		} else {
+			klog.Infof("uploaded support bundle: s3://%s/%s", logBucket, key)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// redactDeployerOptions returns a copy of opts that's safe to share: the values of the --up-cluster-header flags may be credentials,
+// and the --cluster-ready-webhook-url may carry a token in its path or query, so only its scheme and host are kept
+func redactDeployerOptions(opts deployerOptions) deployerOptions {
+	if len(opts.UpClusterHeaders) > 0 {
+		headers := make([]string, 0, len(opts.UpClusterHeaders))
+		for _, header := range opts.UpClusterHeaders {
+			name, _, _ := strings.Cut(header, ":")
+			headers = append(headers, fmt.Sprintf("%s: %s", strings.TrimSpace(name), redacted))
+		}
+		opts.UpClusterHeaders = headers
+	}
+	if opts.ClusterReadyWebhookURL != "" {
+		if u, err := url.Parse(opts.ClusterReadyWebhookURL); err == nil && u.Host != "" {
+			opts.ClusterReadyWebhookURL = fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, redacted)
+		} else {
+			opts.ClusterReadyWebhookURL = redacted
+		}
+	}
+	return opts
+}
+
+// describeAddons returns the description of each of the cluster's managed add-ons
+func (m *logManager) describeAddons(ctx context.Context, clusterName string) ([]ekstypes.Addon, error) {
+	var addons []ekstypes.Addon
+	paginator := eks.NewListAddonsPaginator(m.clients.EKS(), &eks.ListAddonsInput{
+		ClusterName: aws.String(clusterName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list addons: %v", err)
+		}
+		for _, name := range page.Addons {
+			out, err := m.clients.EKS().DescribeAddon(ctx, &eks.DescribeAddonInput{
+				ClusterName: aws.String(clusterName),
+				AddonName:   aws.String(name),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe addon %s: %v", name, err)
+			}
+			addons = append(addons, *out.Addon)
+		}
+	}
+	return addons, nil
+}
+
+// addAddonLogs adds the tail of the log of each container in the add-on namespace to files, as addon-logs/<pod>/<container>.log
+func addAddonLogs(ctx context.Context, k8sClient *k8sClient, files map[string]any) error {
+	pods, err := k8sClient.clientset.CoreV1().Pods(addonNamespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list %s pods: %v", addonNamespace, err)
+	}
+	var errs []error
+	tailLines := int64(addonLogTailLines)
+	for _, pod := range pods.Items {
+		for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			logs, err := k8sClient.clientset.CoreV1().Pods(addonNamespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: container.Name,
+				TailLines: &tailLines,
+			}).DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get logs of %s/%s: %v", pod.Name, container.Name, err))
+				continue
+			}
+			files[fmt.Sprintf("addon-logs/%s/%s.log", pod.Name, container.Name)] = logs
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// supportBundleStackNames returns the CloudFormation stacks the deployer may have created with opts, or without opts, each of the stacks it may create
+func (m *logManager) supportBundleStackNames(opts *deployerOptions /* nillable */) []string {
+	if opts != nil && opts.StaticClusterName != "" {
+		return nil
+	}
+	stackNames := []string{m.resourceID}
+	if opts == nil || opts.UnmanagedNodes {
+		stackNames = append(stackNames, getUnmanagedNodegroupStackName(m.resourceID))
+	}
+	if opts == nil || opts.DeployCloudwatchInfra {
+		stackName, _ := getCloudWatchStackName(m.resourceID)
+		stackNames = append(stackNames, stackName)
+	}
+	return stackNames
+}
+
+func (m *logManager) getStackEvents(ctx context.Context, stackName string) ([]cfntypes.StackEvent, error) {
+	var events []cfntypes.StackEvent
+	paginator := cloudformation.NewDescribeStackEventsPaginator(m.clients.CFN(), &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stackName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.StackEvents...)
+	}
+	return events, nil
+}
+
+// writeSupportBundle returns a gzipped tarball of the files. Byte slices, like logs, are written as is, and other values as indented JSON.
+func writeSupportBundle(files map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for name, value := range files {
+		content, ok := value.([]byte)
+		if !ok {
+			var err error
+			content, err = json.MarshalIndent(value, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %v", name, err)
+			}
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: now,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to write %s to support bundle: %v", name, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, fmt.Errorf("failed to write %s to support bundle: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,69 @@
+package eksapi
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_writeSupportBundle(t *testing.T) {
+	bundle, err := writeSupportBundle(map[string]any{
+		"stack-events/test.json": []string{"CREATE_COMPLETE"},
+	})
+	assert.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "stack-events/test.json", header.Name)
+	content, err := io.ReadAll(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, "[\n  \"CREATE_COMPLETE\"\n]", string(content))
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func Test_writeSupportBundle_bytes(t *testing.T) {
+	bundle, err := writeSupportBundle(map[string]any{
+		"addon-logs/coredns/coredns.log": []byte("ready\n"),
+	})
+	assert.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gz)
+	_, err = tr.Next()
+	assert.NoError(t, err)
+	content, err := io.ReadAll(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, "ready\n", string(content))
+}
+
+func Test_redactDeployerOptions(t *testing.T) {
+	opts := deployerOptions{
+		UpClusterHeaders:       []string{"Authorization: Bearer secret", "X-Api-Key:secret"},
+		ClusterReadyWebhookURL: "https://hooks.example.com/services/secret?token=secret",
+		Region:                 "us-west-2",
+	}
+	redactedOpts := redactDeployerOptions(opts)
+	assert.Equal(t, []string{"Authorization: <redacted>", "X-Api-Key: <redacted>"}, redactedOpts.UpClusterHeaders)
+	assert.Equal(t, "https://hooks.example.com/<redacted>", redactedOpts.ClusterReadyWebhookURL)
+	assert.Equal(t, "us-west-2", redactedOpts.Region)
+	// the deployer's own options are untouched
+	assert.Equal(t, "Authorization: Bearer secret", opts.UpClusterHeaders[0])
+	content, err := json.Marshal(redactedOpts)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "secret")
+}
+
+func Test_supportBundleStackNames(t *testing.T) {
+	m := &logManager{resourceID: "kubetest2-eksapi-test"}
+	assert.Equal(t, []string{"kubetest2-eksapi-test"}, m.supportBundleStackNames(&deployerOptions{}))
+	assert.Nil(t, m.supportBundleStackNames(&deployerOptions{StaticClusterName: "static"}))
+	assert.Len(t, m.supportBundleStackNames(nil), 3)
+}
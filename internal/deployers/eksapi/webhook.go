@@ -0,0 +1,74 @@
+package eksapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const defaultClusterReadyWebhookTimeout = 10 * time.Second
+
+// clusterReadyPayload is the JSON body sent to --cluster-ready-webhook-url
+type clusterReadyPayload struct {
+	Name              string `json:"name"`
+	ARN               string `json:"arn"`
+	Endpoint          string `json:"endpoint"`
+	OIDCIssuer        string `json:"oidcIssuer,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion"`
+	RunID             string `json:"runID"`
+}
+
+// notifyClusterReady tells --cluster-ready-webhook-url that the cluster's API server is reachable, before addons and nodes are created.
+// The webhook is best-effort, so failures are only logged.
+func (d *deployer) notifyClusterReady() {
+	if _, err := d.k8sClient.clientset.Discovery().ServerVersion(); err != nil {
+		klog.Warningf("cluster API server is not reachable, not calling cluster-ready webhook: %v", err)
+		return
+	}
+	timeout := d.ClusterReadyWebhookTimeout
+	if timeout == 0 {
+		timeout = defaultClusterReadyWebhookTimeout
+	}
+	payload := clusterReadyPayload{
+		Name:              d.cluster.name,
+		ARN:               d.cluster.arn,
+		Endpoint:          d.cluster.endpoint,
+		OIDCIssuer:        d.cluster.oidcIssuer,
+		KubernetesVersion: d.KubernetesVersion,
+		RunID:             d.commonOptions.RunID(),
+	}
+	if err := postClusterReady(d.ClusterReadyWebhookURL, timeout, payload); err != nil {
+		klog.Warningf("cluster-ready webhook failed: %v", err)
+		// don't return err, this isn't critical
+		return
+	}
+	klog.Infof("notified cluster-ready webhook: %s", d.ClusterReadyWebhookURL)
+}
+
+func postClusterReady(url string, timeout time.Duration, payload clusterReadyPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return nil
+}
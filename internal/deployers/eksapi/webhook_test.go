@@ -0,0 +1,30 @@
+package eksapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_postClusterReady(t *testing.T) {
+	var received clusterReadyPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Name == "rejected" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	payload := clusterReadyPayload{Name: "test", ARN: "arn:aws:eks:us-west-2:123456789012:cluster/test", Endpoint: "https://example.com", KubernetesVersion: "1.33", RunID: "run"}
+	assert.NoError(t, postClusterReady(server.URL, time.Second, payload))
+	assert.Equal(t, payload, received)
+
+	assert.ErrorContains(t, postClusterReady(server.URL, time.Second, clusterReadyPayload{Name: "rejected"}), "500")
+}
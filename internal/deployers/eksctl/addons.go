@@ -0,0 +1,137 @@
+package eksctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+)
+
+// addonActiveTimeout is how long each --addons addon may take to become ACTIVE after the cluster is created
+const addonActiveTimeout = 15 * time.Minute
+
+// parseAddons parses --addons, each an addon name with an optional =version, e.g. vpc-cni=latest.
+// Addons without a version get EKS's default version for the cluster.
+func parseAddons(specs []string) ([]*eksctl_api.Addon, error) {
+	var addons []*eksctl_api.Addon
+	for _, spec := range specs {
+		name, version, _ := strings.Cut(spec, "=")
+		if name == "" {
+			return nil, fmt.Errorf("--addons expected name[=version]: %s", spec)
+		}
+		if slices.ContainsFunc(addons, func(addon *eksctl_api.Addon) bool { return addon.Name == name }) {
+			return nil, fmt.Errorf("--addons lists %s more than once", name)
+		}
+		addons = append(addons, &eksctl_api.Addon{Name: name, Version: version})
+	}
+	return addons, nil
+}
+
+func (d *deployer) verifyAddons() error {
+	if len(d.Addons) == 0 {
+		if d.AddonConfigurationFile != "" {
+			return fmt.Errorf("--addon-configuration-values-file requires --addons")
+		}
+		return nil
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--addons requires --deploy-target=cluster, addons are created with the cluster")
+	}
+	addons, err := parseAddons(d.Addons)
+	if err != nil {
+		return err
+	}
+	if d.VPCCNIVersion != "" && slices.ContainsFunc(addons, func(addon *eksctl_api.Addon) bool {
+		return addon.Name == eksctl_api.VPCCNIAddon && addon.Version != ""
+	}) {
+		return fmt.Errorf("--addons cannot set the version of %s with --vpc-cni-version", eksctl_api.VPCCNIAddon)
+	}
+	if d.AddonConfigurationFile == "" {
+		return nil
+	}
+	return d.loadAddonConfigurationValues(addons)
+}
+
+// loadAddonConfigurationValues reads the --addon-configuration-values-file, a YAML map of addon names to their configuration values,
+// which are passed to EKS as JSON
+func (d *deployer) loadAddonConfigurationValues(addons []*eksctl_api.Addon) error {
+	data, err := os.ReadFile(d.AddonConfigurationFile)
+	if err != nil {
+		return fmt.Errorf("failed to read --addon-configuration-values-file: %v", err)
+	}
+	var valuesByAddon map[string]map[string]interface{}
+	if err := yaml.UnmarshalStrict(data, &valuesByAddon); err != nil {
+		return fmt.Errorf("failed to parse --addon-configuration-values-file %s: %v", d.AddonConfigurationFile, err)
+	}
+	configurationValues := make(map[string]string)
+	for name, values := range valuesByAddon {
+		if !slices.ContainsFunc(addons, func(addon *eksctl_api.Addon) bool { return addon.Name == name }) {
+			return fmt.Errorf("--addon-configuration-values-file has values for %s, which is not in --addons", name)
+		}
+		if name == eksctl_api.VPCCNIAddon && (d.IPv6 || d.PrefixDelegation) {
+			return fmt.Errorf("--addon-configuration-values-file cannot set the values of %s with --ipv6 or --prefix-delegation, which configure it", name)
+		}
+		encoded, err := json.Marshal(values)
+		if err != nil {
+			return fmt.Errorf("failed to marshal configuration values of addon %s: %v", name, err)
+		}
+		configurationValues[name] = string(encoded)
+	}
+	d.addonConfigurationValues = configurationValues
+	return nil
+}
+
+// addAddons renders the --addons into the cluster config. An addon that another flag already renders, like the VPC CNI for --ipv6,
+// takes its version from --addons if one is given there.
+func (d *deployer) addAddons(cfg *eksctl_api.ClusterConfig) error {
+	addons, err := parseAddons(d.Addons)
+	if err != nil {
+		return err
+	}
+	for _, addon := range addons {
+		addon.ConfigurationValues = d.addonConfigurationValues[addon.Name]
+		i := slices.IndexFunc(cfg.Addons, func(rendered *eksctl_api.Addon) bool { return rendered.Name == addon.Name })
+		if i < 0 {
+			cfg.Addons = append(cfg.Addons, addon)
+			continue
+		}
+		if addon.Version != "" {
+			cfg.Addons[i].Version = addon.Version
+		}
+		if cfg.Addons[i].ConfigurationValues == "" {
+			cfg.Addons[i].ConfigurationValues = addon.ConfigurationValues
+		}
+	}
+	return nil
+}
+
+// waitForAddonsActive waits for each of the --addons to become ACTIVE, so tests don't start before the addons are running
+func (d *deployer) waitForAddonsActive(ctx context.Context) error {
+	addons, err := parseAddons(d.Addons)
+	if err != nil {
+		return err
+	}
+	waiter := eks.NewAddonActiveWaiter(d.eksClient, func(o *eks.AddonActiveWaiterOptions) {
+		o.ClientOptions = append(o.ClientOptions, d.eksRegion)
+	})
+	for _, addon := range addons {
+		klog.Infof("Waiting up to %v for addon %s to become ACTIVE", addonActiveTimeout, addon.Name)
+		if err := waiter.Wait(ctx, &eks.DescribeAddonInput{
+			ClusterName: aws.String(d.clusterName),
+			AddonName:   aws.String(addon.Name),
+		}, addonActiveTimeout); err != nil {
+			return fmt.Errorf("addon %s did not become ACTIVE: %v", addon.Name, err)
+		}
+		klog.Infof("Addon %s is ACTIVE", addon.Name)
+	}
+	return nil
+}
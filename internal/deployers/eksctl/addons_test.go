@@ -0,0 +1,63 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+func Test_parseAddons(t *testing.T) {
+	addons, err := parseAddons([]string{"vpc-cni=latest", "coredns", "aws-ebs-csi-driver=v1.30.0-eksbuild.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*eksctl_api.Addon{
+		{Name: "vpc-cni", Version: "latest"},
+		{Name: "coredns"},
+		{Name: "aws-ebs-csi-driver", Version: "v1.30.0-eksbuild.1"},
+	}, addons)
+
+	_, err = parseAddons([]string{"=latest"})
+	assert.ErrorContains(t, err, "expected name[=version]")
+	_, err = parseAddons([]string{"coredns", "coredns=latest"})
+	assert.ErrorContains(t, err, "lists coredns more than once")
+}
+
+func Test_verifyAddons(t *testing.T) {
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	assert.NoError(t, os.WriteFile(valuesFile, []byte(`coredns:
+  replicaCount: 3
+`), 0644))
+	d := &deployer{UpOptions: &UpOptions{
+		ClusterName:            "test",
+		DeployTarget:           "cluster",
+		IPv6:                   true,
+		Addons:                 []string{"coredns", "kube-proxy=v1.33.0-eksbuild.2", "aws-ebs-csi-driver"},
+		AddonConfigurationFile: valuesFile,
+	}}
+	assert.NoError(t, d.verifyAddons())
+	assert.Equal(t, map[string]string{"coredns": `{"replicaCount":3}`}, d.addonConfigurationValues)
+
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	var rendered []eksctl_api.Addon
+	for _, addon := range cfg.Addons {
+		rendered = append(rendered, eksctl_api.Addon{Name: addon.Name, Version: addon.Version, ConfigurationValues: addon.ConfigurationValues})
+	}
+	assert.Equal(t, []eksctl_api.Addon{
+		{Name: "vpc-cni", Version: "latest"},
+		{Name: "coredns", ConfigurationValues: `{"replicaCount":3}`},
+		{Name: "kube-proxy", Version: "v1.33.0-eksbuild.2"},
+		{Name: "aws-ebs-csi-driver"},
+	}, rendered)
+
+	d.Addons = []string{"kube-proxy"}
+	assert.ErrorContains(t, d.verifyAddons(), "has values for coredns, which is not in --addons")
+
+	d = &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", Addons: []string{"vpc-cni=latest"}, VPCCNIVersion: "1.18.0"}}
+	assert.ErrorContains(t, d.verifyAddons(), "cannot set the version of vpc-cni with --vpc-cni-version")
+
+	d = &deployer{UpOptions: &UpOptions{DeployTarget: "nodegroup", Addons: []string{"coredns"}}}
+	assert.ErrorContains(t, d.verifyAddons(), "requires --deploy-target=cluster")
+}
@@ -0,0 +1,46 @@
+package eksctl
+
+import (
+	"fmt"
+	"slices"
+
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// nodegroupAMIFamilies are the AMI families that --nodegroup-ami-families can mix in one cluster
+var nodegroupAMIFamilies = append([]string{
+	eksctl_api.NodeImageFamilyAmazonLinux2,
+	eksctl_api.NodeImageFamilyAmazonLinux2023,
+	eksctl_api.NodeImageFamilyBottlerocket,
+}, windowsAMIFamilies...)
+
+// verifyNodegroupAMIFamilies checks --nodegroup-ami-families, and that no option that applies to a single AMI family is set with it
+func (d *deployer) verifyNodegroupAMIFamilies() error {
+	if len(d.NodegroupAMIFamilies) == 0 {
+		return nil
+	}
+	for i, amiFamily := range d.NodegroupAMIFamilies {
+		if !slices.Contains(nodegroupAMIFamilies, amiFamily) {
+			return fmt.Errorf("--nodegroup-ami-families must be a list of %v: %s", nodegroupAMIFamilies, amiFamily)
+		}
+		if slices.Contains(d.NodegroupAMIFamilies[:i], amiFamily) {
+			return fmt.Errorf("--nodegroup-ami-families lists %s more than once", amiFamily)
+		}
+	}
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--ami-family", d.AMIFamily != ""},
+		{"--node-ami-type", d.NodeAMIType != ""},
+		{"--ami", d.AMI != ""},
+		{"--kubelet-extra-args", d.KubeletExtraArgs != ""},
+		{"--nodegroup-release-version", d.NodeReleaseVersion != ""},
+	}
+	for _, conflict := range conflicts {
+		if conflict.set {
+			return fmt.Errorf("--nodegroup-ami-families cannot be used with %s, which applies to a single AMI family", conflict.flag)
+		}
+	}
+	return nil
+}
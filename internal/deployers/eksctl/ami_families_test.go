@@ -0,0 +1,46 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyNodegroupAMIFamilies(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{NodegroupAMIFamilies: []string{"AmazonLinux2023", "Bottlerocket"}}}
+	assert.NoError(t, d.verifyNodegroupAMIFamilies())
+
+	d.AMIFamily = "Bottlerocket"
+	assert.ErrorContains(t, d.verifyNodegroupAMIFamilies(), "cannot be used with --ami-family")
+
+	d = &deployer{UpOptions: &UpOptions{NodegroupAMIFamilies: []string{"Ubuntu2204"}}}
+	assert.ErrorContains(t, d.verifyNodegroupAMIFamilies(), "must be a list of")
+
+	d = &deployer{UpOptions: &UpOptions{NodegroupAMIFamilies: []string{"Bottlerocket", "Bottlerocket"}}}
+	assert.ErrorContains(t, d.verifyNodegroupAMIFamilies(), "more than once")
+}
+
+func Test_CreateClusterConfig_nodegroupAMIFamilies(t *testing.T) {
+	d := &deployer{
+		UpOptions: &UpOptions{
+			ClusterName:          "test",
+			AvailabilityZones:    []string{"us-west-2a", "us-west-2b"},
+			NodegroupPerAZ:       true,
+			NodegroupAMIFamilies: []string{"AmazonLinux2023", "Bottlerocket"},
+		},
+	}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	var names, amiFamilies []string
+	for _, mng := range cfg.ManagedNodeGroups {
+		names = append(names, mng.Name)
+		amiFamilies = append(amiFamilies, mng.AMIFamily)
+	}
+	assert.Equal(t, []string{
+		"ng-1-amazonlinux2023-us-west-2a",
+		"ng-1-amazonlinux2023-us-west-2b",
+		"ng-1-bottlerocket-us-west-2a",
+		"ng-1-bottlerocket-us-west-2b",
+	}, names)
+	assert.Equal(t, []string{"AmazonLinux2023", "AmazonLinux2023", "Bottlerocket", "Bottlerocket"}, amiFamilies)
+}
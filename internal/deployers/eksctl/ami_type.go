@@ -0,0 +1,120 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/klog"
+)
+
+// nodeAMIType describes one of the AMI types used by the EKS managed nodegroup API
+type nodeAMIType struct {
+	family       string
+	architecture string
+	// imageIDParameter is the SSM parameter (formatted with the Kubernetes version) holding the recommended AMI ID.
+	// It's only set for variants that eksctl won't choose on its own, based on the AMI family.
+	imageIDParameter string
+}
+
+const (
+	architectureX86_64 = "x86_64"
+	architectureARM64  = "arm64"
+)
+
+var nodeAMITypes = map[string]nodeAMIType{
+	"AL2_x86_64": {
+		family:       eksctl_api.NodeImageFamilyAmazonLinux2,
+		architecture: architectureX86_64,
+	},
+	"AL2_x86_64_GPU": {
+		family:           eksctl_api.NodeImageFamilyAmazonLinux2,
+		architecture:     architectureX86_64,
+		imageIDParameter: "/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/image_id",
+	},
+	"AL2_ARM_64": {
+		family:       eksctl_api.NodeImageFamilyAmazonLinux2,
+		architecture: architectureARM64,
+	},
+	"AL2023_x86_64_STANDARD": {
+		family:       eksctl_api.NodeImageFamilyAmazonLinux2023,
+		architecture: architectureX86_64,
+	},
+	"AL2023_ARM_64_STANDARD": {
+		family:       eksctl_api.NodeImageFamilyAmazonLinux2023,
+		architecture: architectureARM64,
+	},
+	"AL2023_x86_64_NVIDIA": {
+		family:           eksctl_api.NodeImageFamilyAmazonLinux2023,
+		architecture:     architectureX86_64,
+		imageIDParameter: "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/nvidia/recommended/image_id",
+	},
+	"AL2023_ARM_64_NVIDIA": {
+		family:           eksctl_api.NodeImageFamilyAmazonLinux2023,
+		architecture:     architectureARM64,
+		imageIDParameter: "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/nvidia/recommended/image_id",
+	},
+	"AL2023_x86_64_NEURON": {
+		family:           eksctl_api.NodeImageFamilyAmazonLinux2023,
+		architecture:     architectureX86_64,
+		imageIDParameter: "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/neuron/recommended/image_id",
+	},
+	"BOTTLEROCKET_x86_64": {
+		family:       eksctl_api.NodeImageFamilyBottlerocket,
+		architecture: architectureX86_64,
+	},
+	"BOTTLEROCKET_ARM_64": {
+		family:       eksctl_api.NodeImageFamilyBottlerocket,
+		architecture: architectureARM64,
+	},
+	"BOTTLEROCKET_x86_64_NVIDIA": {
+		family:           eksctl_api.NodeImageFamilyBottlerocket,
+		architecture:     architectureX86_64,
+		imageIDParameter: "/aws/service/bottlerocket/aws-k8s-%s-nvidia/x86_64/latest/image_id",
+	},
+	"BOTTLEROCKET_ARM_64_NVIDIA": {
+		family:           eksctl_api.NodeImageFamilyBottlerocket,
+		architecture:     architectureARM64,
+		imageIDParameter: "/aws/service/bottlerocket/aws-k8s-%s-nvidia/arm64/latest/image_id",
+	},
+}
+
+// applyNodeAMIType maps --node-ami-type to the AMI family, and resolves the AMI ID when eksctl can't pick the variant itself
+func (d *deployer) applyNodeAMIType() error {
+	amiType, ok := nodeAMITypes[d.NodeAMIType]
+	if !ok {
+		var known []string
+		for name := range nodeAMITypes {
+			known = append(known, name)
+		}
+		slices.Sort(known)
+		return fmt.Errorf("unknown --node-ami-type %q, supported values: %s", d.NodeAMIType, strings.Join(known, ", "))
+	}
+	if d.AMIFamily != "" && d.AMIFamily != amiType.family {
+		return fmt.Errorf("--node-ami-type %s uses AMI family %s, which conflicts with --ami-family %s", d.NodeAMIType, amiType.family, d.AMIFamily)
+	}
+	if d.nodeArchitecture != "" && d.nodeArchitecture != amiType.architecture {
+		return fmt.Errorf("--node-ami-type %s is for %s, but instance types %v are %s", d.NodeAMIType, amiType.architecture, d.InstanceTypes, d.nodeArchitecture)
+	}
+	d.AMIFamily = amiType.family
+	klog.Infof("using AMI family %s for --node-ami-type %s", d.AMIFamily, d.NodeAMIType)
+	if d.AMI != "" || amiType.imageIDParameter == "" {
+		return nil
+	}
+	parameterName := fmt.Sprintf(amiType.imageIDParameter, d.KubernetesVersion)
+	out, err := d.ssmClient.GetParameter(context.TODO(), &ssm.GetParameterInput{
+		Name: aws.String(parameterName),
+	}, func(o *ssm.Options) {
+		o.Region = d.region()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve AMI for --node-ami-type %s from %s: %v", d.NodeAMIType, parameterName, err)
+	}
+	d.AMI = aws.ToString(out.Parameter.Value)
+	klog.Infof("resolved AMI %s for --node-ami-type %s", d.AMI, d.NodeAMIType)
+	return nil
+}
@@ -0,0 +1,25 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_applyNodeAMIType(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{NodeAMIType: "BOTTLEROCKET_ARM_64"}}
+	assert.NoError(t, d.applyNodeAMIType())
+	assert.Equal(t, "Bottlerocket", d.AMIFamily)
+	assert.Empty(t, d.AMI)
+
+	d = &deployer{UpOptions: &UpOptions{NodeAMIType: "AL2023_x86_64_STANDARD", AMIFamily: "Bottlerocket"}}
+	assert.ErrorContains(t, d.applyNodeAMIType(), "conflicts with --ami-family")
+
+	d = &deployer{UpOptions: &UpOptions{NodeAMIType: "AL2023_x86_64"}}
+	assert.ErrorContains(t, d.applyNodeAMIType(), "unknown --node-ami-type")
+}
+
+func Test_applyNodeAMIType_architecture(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{NodeAMIType: "AL2023_x86_64_STANDARD"}, nodeArchitecture: architectureARM64}
+	assert.ErrorContains(t, d.applyNodeAMIType(), "is for x86_64, but instance types [] are arm64")
+}
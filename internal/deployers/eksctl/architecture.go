@@ -0,0 +1,91 @@
+package eksctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"k8s.io/klog"
+)
+
+// detectNodeArchitecture determines the CPU architecture of the requested instance types, which must all share one.
+// eksctl selects the arm64 variant of the AMI family based on the instance type, so this only needs to
+// catch mixed architectures and an --ami or --node-ami-type built for the other architecture.
+func (d *deployer) detectNodeArchitecture() error {
+	if len(d.InstanceTypes) == 0 {
+		return nil
+	}
+	out, err := d.ec2Client.DescribeInstanceTypes(context.TODO(), &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: toInstanceTypes(d.InstanceTypes),
+	}, d.ec2Region)
+	if err != nil {
+		return fmt.Errorf("failed to describe instance types %v: %v", d.InstanceTypes, err)
+	}
+	architectures := make(map[string][]string)
+	for _, instanceType := range out.InstanceTypes {
+		architecture := instanceTypeArchitecture(instanceType)
+		architectures[architecture] = append(architectures[architecture], string(instanceType.InstanceType))
+	}
+	if len(architectures) > 1 {
+		return fmt.Errorf("instance types must share one architecture: %v", architectures)
+	}
+	for architecture := range architectures {
+		d.nodeArchitecture = architecture
+	}
+	klog.Infof("Detected node architecture %s from instance types %v", d.nodeArchitecture, d.InstanceTypes)
+	return nil
+}
+
+func instanceTypeArchitecture(instanceType ec2types.InstanceTypeInfo) string {
+	if instanceType.ProcessorInfo == nil {
+		return ""
+	}
+	architectures := instanceType.ProcessorInfo.SupportedArchitectures
+	if slices.Contains(architectures, ec2types.ArchitectureTypeArm64) {
+		return architectureARM64
+	}
+	if slices.Contains(architectures, ec2types.ArchitectureTypeX8664) {
+		return architectureX86_64
+	}
+	if len(architectures) > 0 {
+		return string(architectures[0])
+	}
+	return ""
+}
+
+// verifyAMI checks that --ami, or the AMI resolved for --node-ami-type, is available in --region and matches the instance types' architecture.
+// AMI IDs are regional, so this catches an ID copied from another region before the cluster is created.
+func (d *deployer) verifyAMI() error {
+	if d.AMI == "" {
+		return nil
+	}
+	out, err := d.ec2Client.DescribeImages(context.TODO(), &ec2.DescribeImagesInput{
+		ImageIds: []string{d.AMI},
+	}, d.ec2Region)
+	if err != nil {
+		var apierr smithy.APIError
+		if errors.As(err, &apierr) && strings.HasPrefix(apierr.ErrorCode(), "InvalidAMIID.") {
+			return fmt.Errorf("AMI %s not found in %s, AMI IDs are specific to a region: %v", d.AMI, d.region(), err)
+		}
+		return fmt.Errorf("failed to describe AMI %s: %v", d.AMI, err)
+	}
+	if len(out.Images) != 1 {
+		return fmt.Errorf("AMI %s not found in %s, AMI IDs are specific to a region", d.AMI, d.region())
+	}
+	image := out.Images[0]
+	if image.State != ec2types.ImageStateAvailable {
+		return fmt.Errorf("AMI %s is %s in %s, not available", d.AMI, image.State, d.region())
+	}
+	if d.nodeArchitecture == "" {
+		return nil
+	}
+	if architecture := string(image.Architecture); architecture != d.nodeArchitecture {
+		return fmt.Errorf("AMI %s is for %s, but instance types %v are %s", d.AMI, architecture, d.InstanceTypes, d.nodeArchitecture)
+	}
+	return nil
+}
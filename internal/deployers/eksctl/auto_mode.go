@@ -0,0 +1,135 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+const (
+	autoModeSmokeTestPod     = "auto-mode-smoke-test"
+	autoModeSmokeTestImage   = "registry.k8s.io/pause:3.10"
+	autoModeSmokeTestTimeout = 15 * time.Minute
+	// autoModeComputeTypeLabel is set on the nodes that Auto Mode launches
+	autoModeComputeTypeLabel = "eks.amazonaws.com/compute-type"
+)
+
+func (d *deployer) verifyAutoMode() error {
+	if !d.AutoMode {
+		if len(d.AutoModeNodePools) > 0 {
+			return fmt.Errorf("--auto-mode-node-pools requires --auto-mode")
+		}
+		return nil
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--auto-mode requires --deploy-target=cluster, Auto Mode is enabled when the cluster is created")
+	}
+	for i, nodePool := range d.AutoModeNodePools {
+		if !slices.Contains(eksctl_api.AutoModeKnownNodePools, nodePool) {
+			return fmt.Errorf("--auto-mode-node-pools must be a list of %v: %s", eksctl_api.AutoModeKnownNodePools, nodePool)
+		}
+		if slices.Contains(d.AutoModeNodePools[:i], nodePool) {
+			return fmt.Errorf("--auto-mode-node-pools lists %s more than once", nodePool)
+		}
+	}
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--unmanaged-nodegroup", d.UseUnmanagedNodegroup},
+		{"--nodegroups-file", d.NodegroupsFile != ""},
+		{"--nodegroup-ami-families", len(d.NodegroupAMIFamilies) > 0},
+		{"--nodegroup-per-az", d.NodegroupPerAZ},
+		{"--ami", d.AMI != ""},
+		{"--min-ready-nodes", d.MinReadyNodes > 0},
+		{"--node-labels", len(d.NodeLabels) > 0},
+		{"--node-taints", len(d.NodeTaints) > 0},
+		{"--reserve", len(d.Reserve) > 0},
+		{"--imds-hop-limit", d.IMDSHopLimit > 0},
+		{"--efa-enabled", d.EFAEnabled},
+		{"--ssh-public-key", d.SSHPublicKey != ""},
+		{"--enable-ssm", d.EnableSSM},
+		{"--karpenter", d.Karpenter},
+		{"--outpost-arn", d.OutpostARN != ""},
+	}
+	for _, conflict := range conflicts {
+		if conflict.set {
+			return fmt.Errorf("--auto-mode cannot be used with %s, Auto Mode launches its own nodes instead of nodegroups", conflict.flag)
+		}
+	}
+	return nil
+}
+
+// setAutoMode renders the autoModeConfig. Without --auto-mode-node-pools, eksctl creates both of the built-in node pools.
+func (d *deployer) setAutoMode(cfg *eksctl_api.ClusterConfig) {
+	if !d.AutoMode {
+		return
+	}
+	cfg.AutoModeConfig = &eksctl_api.AutoModeConfig{
+		Enabled: aws.Bool(true),
+	}
+	if len(d.AutoModeNodePools) > 0 {
+		nodePools := slices.Clone(d.AutoModeNodePools)
+		cfg.AutoModeConfig.NodePools = &nodePools
+	}
+}
+
+// autoModeSmokeTestPodSpec returns a pod that only Auto Mode nodes can run. It tolerates the system node pool's taint,
+// so either of the built-in node pools can launch a node for it.
+func autoModeSmokeTestPodSpec() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      autoModeSmokeTestPod,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{autoModeComputeTypeLabel: "auto"},
+			Tolerations: []corev1.Toleration{
+				{Key: "CriticalAddonsOnly", Operator: corev1.TolerationOpExists},
+			},
+			Containers: []corev1.Container{
+				{Name: "pause", Image: autoModeSmokeTestImage},
+			},
+		},
+	}
+}
+
+// waitForAutoModeSmokeTest runs a pod on Auto Mode nodes, since the cluster has no nodes until Auto Mode launches one for a pending pod,
+// and deletes it once it's running
+func (d *deployer) waitForAutoModeSmokeTest(ctx context.Context) error {
+	clientset, err := d.kubernetesClient()
+	if err != nil {
+		return err
+	}
+	pods := clientset.CoreV1().Pods(metav1.NamespaceDefault)
+	if _, err := pods.Create(ctx, autoModeSmokeTestPodSpec(), metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create Auto Mode smoke test pod: %v", err)
+	}
+	defer func() {
+		if err := pods.Delete(context.TODO(), autoModeSmokeTestPod, metav1.DeleteOptions{}); err != nil {
+			klog.Warningf("failed to delete Auto Mode smoke test pod: %v", err)
+		}
+	}()
+	klog.Infof("Waiting up to %v for Auto Mode to run pod %s", autoModeSmokeTestTimeout, autoModeSmokeTestPod)
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Second, autoModeSmokeTestTimeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := pods.Get(ctx, autoModeSmokeTestPod, metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("failed to get Auto Mode smoke test pod: %v", err)
+			return false, nil
+		}
+		klog.Infof("Auto Mode smoke test pod is %s on node %q", pod.Status.Phase, pod.Spec.NodeName)
+		return pod.Status.Phase == corev1.PodRunning, nil
+	})
+	if err != nil {
+		return fmt.Errorf("Auto Mode did not run pod %s: %v", autoModeSmokeTestPod, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_verifyAutoMode(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", AutoModeNodePools: []string{"system"}}}
+	assert.ErrorContains(t, d.verifyAutoMode(), "--auto-mode-node-pools requires --auto-mode")
+
+	d.AutoMode = true
+	assert.NoError(t, d.verifyAutoMode())
+
+	d.AutoModeNodePools = []string{"system", "gpu"}
+	assert.ErrorContains(t, d.verifyAutoMode(), "--auto-mode-node-pools must be a list of [general-purpose system]: gpu")
+	d.AutoModeNodePools = []string{"system", "system"}
+	assert.ErrorContains(t, d.verifyAutoMode(), "--auto-mode-node-pools lists system more than once")
+
+	d.AutoModeNodePools = nil
+	d.UseUnmanagedNodegroup = true
+	assert.ErrorContains(t, d.verifyAutoMode(), "--auto-mode cannot be used with --unmanaged-nodegroup")
+
+	d.UseUnmanagedNodegroup = false
+	d.DeployTarget = "nodegroup"
+	assert.ErrorContains(t, d.verifyAutoMode(), "--auto-mode requires --deploy-target=cluster")
+}
+
+func Test_CreateClusterConfig_autoMode(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Nodes: 2, AutoMode: true}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.True(t, *cfg.AutoModeConfig.Enabled)
+	assert.Nil(t, cfg.AutoModeConfig.NodePools)
+	assert.Empty(t, cfg.ManagedNodeGroups)
+	assert.Empty(t, cfg.NodeGroups)
+
+	d.AutoModeNodePools = []string{"general-purpose"}
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"general-purpose"}, *cfg.AutoModeConfig.NodePools)
+}
+
+func Test_autoModeSmokeTestPodSpec(t *testing.T) {
+	pod := autoModeSmokeTestPodSpec()
+	assert.Equal(t, map[string]string{"eks.amazonaws.com/compute-type": "auto"}, pod.Spec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{{Key: "CriticalAddonsOnly", Operator: corev1.TolerationOpExists}}, pod.Spec.Tolerations)
+}
@@ -0,0 +1,67 @@
+package eksctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"sigs.k8s.io/yaml"
+)
+
+// bottlerocketSettingFlags maps the Bottlerocket kubernetes settings that eksctl derives from the nodegroup to the flags that set them
+var bottlerocketSettingFlags = map[string]string{
+	"node-labels": "--node-labels",
+	"node-taints": "--node-taints",
+	"max-pods":    "--prefix-delegation",
+}
+
+// loadBottlerocketSettings reads the --bottlerocket-settings-file, in TOML if the file ends in .toml and YAML otherwise.
+// The settings may be at the top level, or under a settings table as in Bottlerocket user data.
+func (d *deployer) loadBottlerocketSettings() error {
+	data, err := os.ReadFile(d.BottlerocketSettings)
+	if err != nil {
+		return fmt.Errorf("failed to read --bottlerocket-settings-file: %v", err)
+	}
+	var settings map[string]interface{}
+	if filepath.Ext(d.BottlerocketSettings) == ".toml" {
+		err = toml.Unmarshal(data, &settings)
+	} else {
+		err = yaml.Unmarshal(data, &settings)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse --bottlerocket-settings-file %s: %v", d.BottlerocketSettings, err)
+	}
+	if nested, ok := settings["settings"].(map[string]interface{}); ok && len(settings) == 1 {
+		settings = nested
+	}
+	if len(settings) == 0 {
+		return fmt.Errorf("--bottlerocket-settings-file %s has no settings", d.BottlerocketSettings)
+	}
+	if kubernetes, ok := settings["kubernetes"].(map[string]interface{}); ok {
+		for setting, flag := range bottlerocketSettingFlags {
+			if _, ok := kubernetes[setting]; ok {
+				return fmt.Errorf("--bottlerocket-settings-file cannot set kubernetes.%s, which eksctl sets from the nodegroup, use %s instead", setting, flag)
+			}
+		}
+	}
+	if !slices.ContainsFunc(d.nodegroupPlacements(), func(placement nodegroupPlacement) bool {
+		return placement.amiFamily == eksctl_api.NodeImageFamilyBottlerocket
+	}) {
+		return fmt.Errorf("--bottlerocket-settings-file requires a Bottlerocket nodegroup, e.g. --ami-family %s", eksctl_api.NodeImageFamilyBottlerocket)
+	}
+	d.bottlerocketSettingsDoc = settings
+	return nil
+}
+
+// setBottlerocketSettings renders the --bottlerocket-settings-file onto a Bottlerocket nodegroup
+func (d *deployer) setBottlerocketSettings(ng *eksctl_api.NodeGroupBase) {
+	if len(d.bottlerocketSettingsDoc) == 0 || ng.AMIFamily != eksctl_api.NodeImageFamilyBottlerocket {
+		return
+	}
+	// each nodegroup gets its own copy, since eksctl may fill in defaults
+	settings := eksctl_api.InlineDocument(d.bottlerocketSettingsDoc)
+	ng.Bottlerocket = &eksctl_api.NodeGroupBottlerocket{Settings: settings.DeepCopy()}
+}
@@ -0,0 +1,48 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadBottlerocketSettings(t *testing.T) {
+	settingsFile := filepath.Join(t.TempDir(), "settings.toml")
+	assert.NoError(t, os.WriteFile(settingsFile, []byte(`[settings.kernel.sysctl]
+"net.core.somaxconn" = "4096"
+
+[settings.host-containers.admin]
+enabled = true
+`), 0644))
+	d := &deployer{UpOptions: &UpOptions{
+		ClusterName:          "test",
+		AMIFamily:            "Bottlerocket",
+		BottlerocketSettings: settingsFile,
+	}}
+	assert.NoError(t, d.loadBottlerocketSettings())
+	expected := map[string]interface{}{
+		"kernel":          map[string]interface{}{"sysctl": map[string]interface{}{"net.core.somaxconn": "4096"}},
+		"host-containers": map[string]interface{}{"admin": map[string]interface{}{"enabled": true}},
+	}
+	assert.Equal(t, expected, d.bottlerocketSettingsDoc)
+
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, map[string]interface{}(*cfg.ManagedNodeGroups[0].Bottlerocket.Settings))
+
+	settingsFile = filepath.Join(t.TempDir(), "settings.yaml")
+	assert.NoError(t, os.WriteFile(settingsFile, []byte(`kubernetes:
+  node-labels:
+    team: a
+`), 0644))
+	d.BottlerocketSettings = settingsFile
+	assert.ErrorContains(t, d.loadBottlerocketSettings(), "cannot set kubernetes.node-labels, which eksctl sets from the nodegroup, use --node-labels instead")
+
+	assert.NoError(t, os.WriteFile(settingsFile, []byte(`kubernetes:
+  cpu-manager-policy: static
+`), 0644))
+	d.AMIFamily = "AmazonLinux2023"
+	assert.ErrorContains(t, d.loadBottlerocketSettings(), "requires a Bottlerocket nodegroup")
+}
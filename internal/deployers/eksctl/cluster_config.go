@@ -2,8 +2,11 @@ package eksctl
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/utils/ipnet"
 	"k8s.io/klog"
 	"sigs.k8s.io/yaml"
 )
@@ -18,80 +21,309 @@ func (d *deployer) CreateClusterConfig() (*eksctl_api.ClusterConfig, error) {
 	cfg.Metadata.Name = d.clusterName
 	cfg.Metadata.Region = d.Region
 	cfg.Metadata.Version = d.KubernetesVersion
+	tags, err := parseStackTags(d.Tags)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Metadata.Tags = tags
 	// IAM
 	cfg.IAM.WithOIDC = &d.WithOIDC
-
-	amiFamily := d.AMIFamily
-	if amiFamily == "" {
-		amiFamily = eksctl_api.NodeImageFamilyAmazonLinux2
-	}
-	nodeGroupName := d.NodegroupName
-	if nodeGroupName == "" {
-		nodeGroupName = "ng-1"
-	}
-	// Create node group or managed node group (MNG)
-	if d.UseUnmanagedNodegroup {
-		ng := cfg.NewNodeGroup()
-		// TODO: update this when we add support for SSH.
-		ng.SSH = nil
-		ng.AMIFamily = amiFamily
-		ng.Name = nodeGroupName
-		if len(d.InstanceTypes) > 0 {
-			ng.InstanceType = d.InstanceTypes[0]
+	// IPv6 and prefix delegation need the VPC CNI to be a managed addon
+	if d.IPv6 || d.PrefixDelegation {
+		vpcCNIVersion := d.VPCCNIVersion
+		if vpcCNIVersion == "" {
+			vpcCNIVersion = "latest"
 		}
-		if d.Nodes >= 0 {
-			ng.MinSize = &d.Nodes
-			ng.MaxSize = &d.Nodes
-			ng.DesiredCapacity = &d.Nodes
+		configurationValues, err := d.vpcCNIConfigurationValues()
+		if err != nil {
+			return nil, err
 		}
-		if d.VolumeSize >= 0 {
-			ng.VolumeSize = &d.VolumeSize
+		cfg.Addons = []*eksctl_api.Addon{
+			{Name: eksctl_api.VPCCNIAddon, Version: vpcCNIVersion, ConfigurationValues: configurationValues},
 		}
-		ng.PrivateNetworking = d.PrivateNetworking
-		ng.EFAEnabled = &d.EFAEnabled
-		if len(d.AvailabilityZones) > 0 {
-			ng.AvailabilityZones = d.AvailabilityZones
-		}
-		if d.AMI != "" && amiFamily == eksctl_api.NodeImageFamilyAmazonLinux2 {
-			bootstrapCommand := fmt.Sprintf(`#!/bin/bash
-source /var/lib/cloud/scripts/eksctl/bootstrap.helper.sh
-/etc/eks/bootstrap.sh %s --kubelet-extra-args "--node-labels=${NODE_LABELS}"`, d.clusterName)
-			ng.OverrideBootstrapCommand = &bootstrapCommand
+	}
+	// eksctl requires the other core addons to be managed too for IPv6
+	if d.IPv6 {
+		cfg.KubernetesNetworkConfig = &eksctl_api.KubernetesNetworkConfig{
+			IPFamily: eksctl_api.IPV6Family,
 		}
-	} else {
-		// Create managed node group
-		mng := eksctl_api.NewManagedNodeGroup()
-		cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, mng)
-		// TODO: update this when we add support for SSH.
-		mng.SSH = nil
-		mng.AMIFamily = amiFamily
-		mng.Name = nodeGroupName
-		mng.InstanceTypes = d.InstanceTypes
-		if d.Nodes >= 0 {
-			mng.MinSize = &d.Nodes
-			mng.MaxSize = &d.Nodes
-			mng.DesiredCapacity = &d.Nodes
+		cfg.Addons = append(cfg.Addons,
+			&eksctl_api.Addon{Name: eksctl_api.CoreDNSAddon},
+			&eksctl_api.Addon{Name: eksctl_api.KubeProxyAddon},
+		)
+	}
+	if d.ContainerInsights {
+		cfg.Addons = append(cfg.Addons, d.containerInsightsAddonConfig())
+	}
+	if err := d.addAddons(cfg); err != nil {
+		return nil, err
+	}
+	fargateProfiles, err := parseFargateProfiles(d.FargateProfiles)
+	if err != nil {
+		return nil, err
+	}
+	cfg.FargateProfiles = fargateProfiles
+	d.setKarpenter(cfg)
+	d.setSecretsEncryption(cfg)
+	d.setClusterLogging(cfg)
+	// VPC
+	if d.VPCCIDR != "" {
+		cidr, err := ipnet.ParseCIDR(d.VPCCIDR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse --vpc-cidr: %v", err)
 		}
-		if d.VolumeSize >= 0 {
-			mng.VolumeSize = &d.VolumeSize
+		cfg.VPC.CIDR = cidr
+	}
+	d.setExistingVPC(cfg)
+	d.setEndpointAccess(cfg)
+	d.setAutoMode(cfg)
+	if d.AutoMode {
+		// Auto Mode launches the nodes itself
+		return cfg, nil
+	}
+
+	// Create node groups or managed node groups (MNG)
+	for _, placement := range d.nodegroupPlacements() {
+		amiFamily := placement.amiFamily
+		if amiFamily == "" {
+			amiFamily = eksctl_api.NodeImageFamilyAmazonLinux2
 		}
-		mng.PrivateNetworking = d.PrivateNetworking
-		mng.EFAEnabled = &d.EFAEnabled
-		if len(d.AvailabilityZones) > 0 {
-			mng.AvailabilityZones = d.AvailabilityZones
+		var err error
+		if d.UseUnmanagedNodegroup {
+			err = d.addNodeGroup(cfg, amiFamily, placement)
+		} else {
+			err = d.addManagedNodeGroup(cfg, amiFamily, placement)
 		}
-		if d.AMI != "" && amiFamily == eksctl_api.NodeImageFamilyAmazonLinux2 {
-			bootstrapCommand := fmt.Sprintf(`#!/bin/bash
-source /var/lib/cloud/scripts/eksctl/bootstrap.helper.sh
-/etc/eks/bootstrap.sh %s --kubelet-extra-args "--node-labels=${NODE_LABELS}"`, d.clusterName)
-			mng.OverrideBootstrapCommand = &bootstrapCommand
-		} else if d.AMI != "" && amiFamily == eksctl_api.NodeImageFamilyBottlerocket {
-			mng.AMI = d.AMI
+		if err != nil {
+			return nil, err
 		}
 	}
 	return cfg, nil
 }
 
+func (d *deployer) addNodeGroup(cfg *eksctl_api.ClusterConfig, amiFamily string, placement nodegroupPlacement) error {
+	labels, taints, err := d.nodeLabelsAndTaints()
+	if err != nil {
+		return err
+	}
+	ng := cfg.NewNodeGroup()
+	d.setSSHAccess(ng.NodeGroupBase)
+	ng.AMIFamily = amiFamily
+	ng.Name = placement.name
+	ng.Tags = d.nodeTags(placement.name)
+	ng.Labels = labels
+	ng.Taints = taints
+	if d.Spot {
+		setSpotInstancesDistribution(ng, d.placementInstanceTypes(placement))
+	} else if instanceTypes := d.placementInstanceTypes(placement); len(instanceTypes) > 0 {
+		ng.InstanceType = instanceTypes[0]
+	}
+	if d.Nodes >= 0 {
+		ng.MinSize, ng.MaxSize, ng.DesiredCapacity = d.placementSize(placement)
+	}
+	if d.VolumeSize >= 0 {
+		ng.VolumeSize = d.placementVolumeSize(placement)
+	}
+	d.setVolumeOptions(ng.NodeGroupBase)
+	d.setBottlerocketSettings(ng.NodeGroupBase)
+	if eksctl_api.IsWindowsImage(amiFamily) {
+		setWindowsOptions(ng.NodeGroupBase)
+		// eksctl otherwise defaults Windows nodes to Docker for the Kubernetes versions that still supported dockershim
+		ng.ContainerRuntime = aws.String(eksctl_api.ContainerRuntimeContainerD)
+	}
+	ng.PrivateNetworking = d.PrivateNetworking
+	ng.MaxPodsPerNode = d.maxPodsPerNode
+	ng.EFAEnabled = &d.EFAEnabled
+	d.setEFAPlacement(ng.NodeGroupBase)
+	if d.EnableFullECRAccess {
+		ng.IAM.WithAddonPolicies.ImageBuilder = eksctl_api.Enabled()
+	}
+	if len(placement.availabilityZones) > 0 {
+		ng.AvailabilityZones = placement.availabilityZones
+	}
+	if len(d.NodeSubnetIDs) > 0 {
+		ng.Subnets = d.NodeSubnetIDs
+	}
+	ng.OutpostARN = d.OutpostARN
+	bootstrapCommand, err := d.overrideBootstrapCommand(amiFamily)
+	if err != nil {
+		return err
+	}
+	ng.OverrideBootstrapCommand = bootstrapCommand
+	if d.AMI != "" && amiFamily == eksctl_api.NodeImageFamilyAmazonLinux2023 {
+		ng.AMI = d.AMI
+	}
+	return nil
+}
+
+func (d *deployer) addManagedNodeGroup(cfg *eksctl_api.ClusterConfig, amiFamily string, placement nodegroupPlacement) error {
+	labels, taints, err := d.nodeLabelsAndTaints()
+	if err != nil {
+		return err
+	}
+	mng := eksctl_api.NewManagedNodeGroup()
+	cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, mng)
+	d.setSSHAccess(mng.NodeGroupBase)
+	mng.AMIFamily = amiFamily
+	mng.Name = placement.name
+	mng.Tags = d.nodeTags(placement.name)
+	mng.Labels = labels
+	mng.Taints = taints
+	mng.InstanceTypes = d.placementInstanceTypes(placement)
+	mng.Spot = d.Spot
+	if d.Nodes >= 0 {
+		mng.MinSize, mng.MaxSize, mng.DesiredCapacity = d.placementSize(placement)
+	}
+	if d.VolumeSize >= 0 {
+		mng.VolumeSize = d.placementVolumeSize(placement)
+	}
+	d.setVolumeOptions(mng.NodeGroupBase)
+	d.setBottlerocketSettings(mng.NodeGroupBase)
+	if eksctl_api.IsWindowsImage(amiFamily) {
+		setWindowsOptions(mng.NodeGroupBase)
+	}
+	mng.PrivateNetworking = d.PrivateNetworking
+	mng.MaxPodsPerNode = d.maxPodsPerNode
+	mng.ReleaseVersion = d.NodeReleaseVersion
+	mng.EFAEnabled = &d.EFAEnabled
+	d.setEFAPlacement(mng.NodeGroupBase)
+	if d.EnableFullECRAccess {
+		mng.IAM.WithAddonPolicies.ImageBuilder = eksctl_api.Enabled()
+	}
+	if len(placement.availabilityZones) > 0 {
+		mng.AvailabilityZones = placement.availabilityZones
+	}
+	if len(d.NodeSubnetIDs) > 0 {
+		mng.Subnets = d.NodeSubnetIDs
+	}
+	bootstrapCommand, err := d.overrideBootstrapCommand(amiFamily)
+	if err != nil {
+		return err
+	}
+	mng.OverrideBootstrapCommand = bootstrapCommand
+	if d.AMI != "" && (amiFamily == eksctl_api.NodeImageFamilyBottlerocket || amiFamily == eksctl_api.NodeImageFamilyAmazonLinux2023) {
+		mng.AMI = d.AMI
+	}
+	return nil
+}
+
+// nodegroupPlacement is one of the nodegroups created by the deployer.
+// The instance types, nodes, and volume size are only set for nodegroups from the --nodegroups-file, and otherwise come from the flags.
+type nodegroupPlacement struct {
+	name              string
+	availabilityZones []string
+	amiFamily         string
+	instanceTypes     []string
+	nodes             int
+	volumeSize        int
+}
+
+// nodegroupPlacements returns the nodegroups created by the deployer.
+// With --nodegroups-file, these are the nodegroups in the file.
+// With --nodegroup-ami-families, the nodegroup is replicated for each AMI family, suffixed with the lower-cased family name.
+// With --nodegroup-per-az, each of those is replicated into each availability zone, suffixed with the zone name.
+func (d *deployer) nodegroupPlacements() []nodegroupPlacement {
+	base := []nodegroupPlacement{{name: d.nodegroupName(), amiFamily: d.AMIFamily}}
+	if len(d.nodegroupSpecs) > 0 {
+		base = nil
+		for _, spec := range d.nodegroupSpecs {
+			amiFamily := spec.AMIFamily
+			if amiFamily == "" {
+				amiFamily = d.AMIFamily
+			}
+			base = append(base, nodegroupPlacement{
+				name:          spec.Name,
+				amiFamily:     amiFamily,
+				instanceTypes: spec.InstanceTypes,
+				nodes:         spec.Nodes,
+				volumeSize:    spec.VolumeSize,
+			})
+		}
+	} else if len(d.NodegroupAMIFamilies) > 0 {
+		base = nil
+		for _, amiFamily := range d.NodegroupAMIFamilies {
+			base = append(base, nodegroupPlacement{
+				name:      fmt.Sprintf("%s-%s", d.nodegroupName(), strings.ToLower(amiFamily)),
+				amiFamily: amiFamily,
+			})
+		}
+	}
+	if !d.NodegroupPerAZ {
+		for i := range base {
+			base[i].availabilityZones = d.AvailabilityZones
+		}
+		return base
+	}
+	var placements []nodegroupPlacement
+	for _, placement := range base {
+		for _, availabilityZone := range d.AvailabilityZones {
+			zonePlacement := placement
+			zonePlacement.name = fmt.Sprintf("%s-%s", placement.name, availabilityZone)
+			zonePlacement.availabilityZones = []string{availabilityZone}
+			placements = append(placements, zonePlacement)
+		}
+	}
+	return placements
+}
+
+// nodegroupNames returns the names of the nodegroups created by the deployer
+func (d *deployer) nodegroupNames() []string {
+	var names []string
+	for _, placement := range d.nodegroupPlacements() {
+		names = append(names, placement.name)
+	}
+	return names
+}
+
+// minSize returns the nodegroup's minimum size, which eksctl waits for to become Ready after creating the nodegroup
+func (d *deployer) minSize() *int {
+	if d.MinReadyNodes > 0 {
+		return &d.MinReadyNodes
+	}
+	return &d.Nodes
+}
+
+// desiredCapacity returns the nodegroup's initial size, --desired-capacity if set or --nodes
+func (d *deployer) desiredCapacity() *int {
+	if d.DesiredCapacity > 0 {
+		return &d.DesiredCapacity
+	}
+	return &d.Nodes
+}
+
+// placementInstanceTypes returns the nodegroup's instance types, from the --nodegroups-file or --instance-types
+func (d *deployer) placementInstanceTypes(placement nodegroupPlacement) []string {
+	if len(placement.instanceTypes) > 0 {
+		return placement.instanceTypes
+	}
+	return d.InstanceTypes
+}
+
+// placementSize returns the nodegroup's minimum, maximum, and desired size.
+// The nodes of a --nodegroups-file entry sets all three, otherwise they come from the flags.
+func (d *deployer) placementSize(placement nodegroupPlacement) (*int, *int, *int) {
+	if placement.nodes > 0 {
+		return &placement.nodes, &placement.nodes, &placement.nodes
+	}
+	return d.minSize(), &d.Nodes, d.desiredCapacity()
+}
+
+// placementVolumeSize returns the nodegroup's root volume size, from the --nodegroups-file or --volume-size
+func (d *deployer) placementVolumeSize(placement nodegroupPlacement) *int {
+	if placement.volumeSize > 0 {
+		return &placement.volumeSize
+	}
+	return &d.VolumeSize
+}
+
+// nodegroupName returns the name of the nodegroup created by the deployer, or the prefix of the per-AZ nodegroups
+func (d *deployer) nodegroupName() string {
+	if d.NodegroupName == "" {
+		return "ng-1"
+	}
+	return d.NodegroupName
+}
+
 type clusterConfigTemplateParams struct {
 	UpOptions
 	ClusterName string
@@ -102,8 +334,12 @@ func (d *deployer) RenderClusterConfig() ([]byte, error) {
 
 	cfg, err := d.CreateClusterConfig()
 	if err != nil {
-		klog.Errorf("failed to create ClusterConfig with the deployer: %v", err)
+		return nil, fmt.Errorf("failed to create ClusterConfig: %v", err)
 	}
 	klog.Infof("rendering cluster config yaml based on the ClusterConfig: %v", cfg)
-	return yaml.Marshal(cfg)
+	clusterConfig, err := yaml.Marshal(cfg)
+	if err != nil || d.ConfigPatch == "" {
+		return clusterConfig, err
+	}
+	return applyConfigPatch(clusterConfig, d.ConfigPatch)
 }
@@ -0,0 +1,106 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateClusterConfig_nodegroupPerAZ(t *testing.T) {
+	d := &deployer{
+		UpOptions: &UpOptions{
+			ClusterName:       "test",
+			Nodes:             2,
+			AvailabilityZones: []string{"us-west-2a", "us-west-2b"},
+			NodegroupPerAZ:    true,
+		},
+	}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Len(t, cfg.ManagedNodeGroups, 2)
+	for i, availabilityZone := range d.AvailabilityZones {
+		assert.Equal(t, "ng-1-"+availabilityZone, cfg.ManagedNodeGroups[i].Name)
+		assert.Equal(t, []string{availabilityZone}, cfg.ManagedNodeGroups[i].AvailabilityZones)
+		assert.Equal(t, 2, *cfg.ManagedNodeGroups[i].DesiredCapacity)
+	}
+}
+
+func Test_CreateClusterConfig_vpcCIDR(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", VPCCIDR: "10.100.0.0/16"}}
+	assert.NoError(t, d.verifyVPCCIDR())
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.100.0.0/16", cfg.VPC.CIDR.String())
+
+	d.VPCCIDR = "10.100.0.1/16"
+	assert.ErrorContains(t, d.verifyVPCCIDR(), "did you mean 10.100.0.0/16")
+	d.VPCCIDR = "10.100.0.0/26"
+	assert.ErrorContains(t, d.verifyVPCCIDR(), "prefix length must be between /16 and /24")
+}
+
+func Test_CreateClusterConfig_nodeNamePrefix(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", NodeNamePrefix: "ci", UseUnmanagedNodegroup: true}}
+	assert.NoError(t, d.verifyNodeNamePrefix())
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Name": "ci-ng-1"}, cfg.NodeGroups[0].Tags)
+
+	d.NodeNamePrefix = "-ci"
+	assert.ErrorContains(t, d.verifyNodeNamePrefix(), "--node-name-prefix must be")
+}
+
+func Test_CreateClusterConfig_ipv6(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", KubernetesVersion: "1.33", IPv6: true}}
+	assert.NoError(t, d.verifyIPv6())
+	assert.True(t, d.WithOIDC)
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.True(t, cfg.IPv6Enabled())
+	assert.True(t, *cfg.IAM.WithOIDC)
+	assert.Equal(t, "latest", cfg.Addons[0].Version)
+	var addonNames []string
+	for _, addon := range cfg.Addons {
+		addonNames = append(addonNames, addon.Name)
+	}
+	assert.Equal(t, []string{"vpc-cni", "coredns", "kube-proxy"}, addonNames)
+
+	d.VPCCNIVersion = "v1.9.3-eksbuild.1"
+	assert.ErrorContains(t, d.verifyIPv6(), "--ipv6 requires --vpc-cni-version 1.10.0 or later")
+	d.VPCCNIVersion = "v1.19.2-eksbuild.1"
+	assert.NoError(t, d.verifyIPv6())
+	d.KubernetesVersion = "1.20"
+	assert.ErrorContains(t, d.verifyIPv6(), "--ipv6 requires --kubernetes-version 1.21 or later")
+
+	d = &deployer{UpOptions: &UpOptions{VPCCNIVersion: "latest"}}
+	assert.ErrorContains(t, d.verifyIPv6(), "--vpc-cni-version requires --ipv6 or --prefix-delegation")
+}
+
+func Test_verifyIPFamily(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", IPFamily: "IPv6"}}
+	assert.NoError(t, d.verifyIPFamily())
+	assert.True(t, d.IPv6)
+	assert.NoError(t, d.verifyIPv6())
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "IPv6", cfg.KubernetesNetworkConfig.IPFamily)
+
+	d.IPFamily = "ipv4"
+	assert.ErrorContains(t, d.verifyIPFamily(), "--ip-family ipv4 cannot be used with --ipv6")
+
+	d = &deployer{UpOptions: &UpOptions{IPFamily: "dual"}}
+	assert.ErrorContains(t, d.verifyIPFamily(), "--ip-family must be ipv4 or ipv6")
+}
+
+func Test_CreateClusterConfig_desiredCapacity(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Nodes: 10, MinReadyNodes: 1, DesiredCapacity: 1}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *cfg.ManagedNodeGroups[0].MinSize)
+	assert.Equal(t, 1, *cfg.ManagedNodeGroups[0].DesiredCapacity)
+	assert.Equal(t, 10, *cfg.ManagedNodeGroups[0].MaxSize)
+
+	d = &deployer{UpOptions: &UpOptions{ClusterName: "test", Nodes: 3}}
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *cfg.ManagedNodeGroups[0].DesiredCapacity)
+}
@@ -0,0 +1,92 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/klog"
+)
+
+// allClusterLogTypes enables each of the control plane log types, the same as eksctl's shorthand
+const allClusterLogTypes = "all"
+
+// clusterLogTypes returns the --enable-cluster-logging types, with "all" expanded
+func (d *deployer) clusterLogTypes() []string {
+	if slices.Contains(d.ClusterLogging, allClusterLogTypes) {
+		return eksctl_api.SupportedCloudWatchClusterLogTypes()
+	}
+	return d.ClusterLogging
+}
+
+func (d *deployer) verifyClusterLogging() error {
+	if len(d.ClusterLogging) == 0 {
+		if d.ClusterLogRetention != 0 {
+			return fmt.Errorf("--cluster-log-retention-days requires --enable-cluster-logging")
+		}
+		return nil
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--enable-cluster-logging requires --deploy-target=cluster, control plane logging is enabled when the cluster is created")
+	}
+	supported := eksctl_api.SupportedCloudWatchClusterLogTypes()
+	for i, logType := range d.ClusterLogging {
+		if logType != allClusterLogTypes && !slices.Contains(supported, logType) {
+			return fmt.Errorf("--enable-cluster-logging must be a list of %v, or %s: %s", supported, allClusterLogTypes, logType)
+		}
+		if slices.Contains(d.ClusterLogging[:i], logType) {
+			return fmt.Errorf("--enable-cluster-logging lists %s more than once", logType)
+		}
+	}
+	if d.ClusterLogRetention != 0 && !slices.Contains(eksctl_api.LogRetentionInDaysValues, d.ClusterLogRetention) {
+		return fmt.Errorf("--cluster-log-retention-days must be one of CloudWatch Logs' retention periods %v: %d", eksctl_api.LogRetentionInDaysValues, d.ClusterLogRetention)
+	}
+	return nil
+}
+
+// setClusterLogging renders the control plane log types, which EKS sends to the /aws/eks/<cluster>/cluster log group.
+// Without --cluster-log-retention-days, the log group keeps the logs indefinitely.
+func (d *deployer) setClusterLogging(cfg *eksctl_api.ClusterConfig) {
+	if len(d.ClusterLogging) == 0 {
+		return
+	}
+	cfg.CloudWatch = &eksctl_api.ClusterCloudWatch{
+		ClusterLogging: &eksctl_api.ClusterCloudWatchLogging{
+			EnableTypes:        d.clusterLogTypes(),
+			LogRetentionInDays: d.ClusterLogRetention,
+		},
+	}
+}
+
+// checkClusterLogging checks that EKS enabled each of the --enable-cluster-logging types on the cluster
+func (d *deployer) checkClusterLogging(ctx context.Context) error {
+	if len(d.ClusterLogging) == 0 {
+		return nil
+	}
+	out, err := d.eksClient.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(d.clusterName),
+	}, d.eksRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe cluster %s: %v", d.clusterName, err)
+	}
+	var enabled []string
+	if out.Cluster.Logging != nil {
+		for _, setup := range out.Cluster.Logging.ClusterLogging {
+			if aws.ToBool(setup.Enabled) {
+				for _, logType := range setup.Types {
+					enabled = append(enabled, string(logType))
+				}
+			}
+		}
+	}
+	for _, logType := range d.clusterLogTypes() {
+		if !slices.Contains(enabled, logType) {
+			return fmt.Errorf("cluster %s has control plane logging enabled for %v, not %s", d.clusterName, enabled, logType)
+		}
+	}
+	klog.Infof("Control plane logs %v of cluster %s are sent to CloudWatch log group /aws/eks/%s/cluster", d.clusterLogTypes(), d.clusterName, d.clusterName)
+	return nil
+}
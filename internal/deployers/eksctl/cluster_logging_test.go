@@ -0,0 +1,50 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyClusterLogging(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{DeployTarget: "cluster"}}
+	assert.NoError(t, d.verifyClusterLogging())
+	d.ClusterLogRetention = 7
+	assert.ErrorContains(t, d.verifyClusterLogging(), "--cluster-log-retention-days requires --enable-cluster-logging")
+
+	d.ClusterLogging = []string{"api", "audit", "authenticator"}
+	assert.NoError(t, d.verifyClusterLogging())
+	d.ClusterLogRetention = 8
+	assert.ErrorContains(t, d.verifyClusterLogging(), "--cluster-log-retention-days must be one of")
+
+	d.ClusterLogRetention = 0
+	d.ClusterLogging = []string{"all"}
+	assert.NoError(t, d.verifyClusterLogging())
+	d.ClusterLogging = []string{"api", "kubelet"}
+	assert.ErrorContains(t, d.verifyClusterLogging(), "--enable-cluster-logging must be a list of")
+	d.ClusterLogging = []string{"api", "api"}
+	assert.ErrorContains(t, d.verifyClusterLogging(), "lists api more than once")
+
+	d.ClusterLogging = []string{"api"}
+	d.DeployTarget = "nodegroup"
+	assert.ErrorContains(t, d.verifyClusterLogging(), "requires --deploy-target=cluster")
+}
+
+func Test_CreateClusterConfig_clusterLogging(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test"}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.False(t, cfg.HasClusterCloudWatchLogging())
+
+	d.ClusterLogging = []string{"api", "audit"}
+	d.ClusterLogRetention = 14
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api", "audit"}, cfg.CloudWatch.ClusterLogging.EnableTypes)
+	assert.Equal(t, 14, cfg.CloudWatch.ClusterLogging.LogRetentionInDays)
+
+	d.ClusterLogging = []string{"all"}
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api", "audit", "authenticator", "controllerManager", "scheduler"}, cfg.CloudWatch.ClusterLogging.EnableTypes)
+}
@@ -0,0 +1,142 @@
+package eksctl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/internal/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"k8s.io/klog"
+	"sigs.k8s.io/kubetest2/pkg/artifacts"
+)
+
+const (
+	clusterInfoDumpDir = "cluster-info"
+	nodeLogsDir        = "node-logs"
+	// nodeJournalCommandTimeout is how long Run Command may take to read a journal on each of the nodes
+	nodeJournalCommandTimeout = 5 * time.Minute
+	// nodeJournalTailBytes keeps a journal within the 24,000 characters of output that Run Command returns,
+	// once it's compressed and base64 encoded
+	nodeJournalTailBytes = 120000
+	// ssmMaxInstanceIDs is the most instances a Run Command can target by ID
+	ssmMaxInstanceIDs = 50
+)
+
+// nodeJournalUnits are the systemd units whose journals are dumped from each node
+var nodeJournalUnits = []string{"kubelet", "containerd"}
+
+// dumpClusterInfo writes the output of kubectl cluster-info dump for all namespaces to the artifacts directory
+func (d *deployer) dumpClusterInfo(ctx context.Context) error {
+	kubeconfig, err := d.Kubeconfig()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(kubeconfig); err != nil {
+		return fmt.Errorf("no kubeconfig for cluster %s, the cluster may not have been created: %v", d.clusterName, err)
+	}
+	outputDir := filepath.Join(artifacts.BaseDir(), clusterInfoDumpDir)
+	if err := util.ExecuteCommandContext(ctx, "kubectl", "--kubeconfig", kubeconfig, "cluster-info", "dump", "--all-namespaces", "--output-directory", outputDir); err != nil {
+		return fmt.Errorf("failed to dump cluster info: %v", err)
+	}
+	klog.Infof("wrote cluster info dump: %s", outputDir)
+	return nil
+}
+
+// nodeJournalCommand returns the shell command that prints the tail of a unit's journal, gzipped and base64 encoded
+func nodeJournalCommand(unit string) string {
+	return fmt.Sprintf("journalctl -u %s --no-pager -o short-iso | tail -c %d | gzip | base64 -w 0", unit, nodeJournalTailBytes)
+}
+
+// decodeNodeJournal decodes the output of a nodeJournalCommand
+func decodeNodeJournal(output string) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode journal: %v", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress journal: %v", err)
+	}
+	defer reader.Close()
+	journal, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress journal: %v", err)
+	}
+	return journal, nil
+}
+
+// dumpNodeJournals writes the kubelet and containerd journals of the nodegroups' instances to the artifacts directory,
+// read with Run Command through the SSM agent that eksctl's nodes run. Only the tail of each journal fits in Run Command's output.
+func (d *deployer) dumpNodeJournals(ctx context.Context) error {
+	instanceIDs, err := d.getNodegroupInstanceIDs()
+	if err != nil {
+		return err
+	}
+	outputDir := filepath.Join(artifacts.BaseDir(), nodeLogsDir)
+	var errs []error
+	for _, unit := range nodeJournalUnits {
+		for batch := range slices.Chunk(instanceIDs, ssmMaxInstanceIDs) {
+			if err := d.dumpNodeJournal(ctx, unit, batch, outputDir); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	klog.Infof("wrote node journals of instances %v: %s", instanceIDs, outputDir)
+	return nil
+}
+
+// dumpNodeJournal writes a unit's journal on each of the instances to <outputDir>/<instance ID>/<unit>.log
+func (d *deployer) dumpNodeJournal(ctx context.Context, unit string, instanceIDs []string, outputDir string) error {
+	command, err := d.ssmClient.SendCommand(ctx, &ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  instanceIDs,
+		Comment:      aws.String(fmt.Sprintf("kubetest2-eksctl %s journal of cluster %s", unit, d.clusterName)),
+		Parameters: map[string][]string{
+			"commands": {nodeJournalCommand(unit)},
+		},
+	}, d.ssmRegion)
+	if err != nil {
+		return fmt.Errorf("failed to send command for %s journals: %v", unit, err)
+	}
+	var errs []error
+	waiter := ssm.NewCommandExecutedWaiter(d.ssmClient)
+	for _, instanceID := range instanceIDs {
+		out, err := waiter.WaitForOutput(ctx, &ssm.GetCommandInvocationInput{
+			CommandId:  command.Command.CommandId,
+			InstanceId: aws.String(instanceID),
+		}, nodeJournalCommandTimeout, func(o *ssm.CommandExecutedWaiterOptions) {
+			o.ClientOptions = append(o.ClientOptions, d.ssmRegion)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read %s journal of %s: %v", unit, instanceID, err))
+			continue
+		}
+		journal, err := decodeNodeJournal(aws.ToString(out.StandardOutputContent))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read %s journal of %s: %v", unit, instanceID, err))
+			continue
+		}
+		instanceDir := filepath.Join(outputDir, instanceID)
+		if err := os.MkdirAll(instanceDir, 0755); err != nil {
+			return fmt.Errorf("failed to create node logs directory: %v", err)
+		}
+		outputPath := filepath.Join(instanceDir, unit+".log")
+		if err := os.WriteFile(outputPath, journal, 0644); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write %s: %v", outputPath, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,34 @@
+package eksctl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nodeJournalCommand(t *testing.T) {
+	assert.Equal(t, "journalctl -u kubelet --no-pager -o short-iso | tail -c 120000 | gzip | base64 -w 0", nodeJournalCommand("kubelet"))
+}
+
+func Test_decodeNodeJournal(t *testing.T) {
+	journal := "2026-01-01T00:00:00+0000 ip-10-0-0-1 kubelet[1234]: Started kubelet\n"
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(journal))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	decoded, err := decodeNodeJournal(base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, journal, string(decoded))
+
+	_, err = decodeNodeJournal("not base64!")
+	assert.ErrorContains(t, err, "failed to decode journal")
+	// Run Command cuts off output past 24,000 characters
+	truncated := base64.StdEncoding.EncodeToString(compressed.Bytes()[:compressed.Len()/2])
+	_, err = decodeNodeJournal(truncated)
+	assert.ErrorContains(t, err, "failed to decompress journal")
+}
@@ -0,0 +1,65 @@
+package eksctl
+
+import (
+	"fmt"
+
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"sigs.k8s.io/yaml"
+)
+
+// parseConfigPatch parses the --config-patch snippet, which may be JSON or YAML
+func parseConfigPatch(patch string) (map[string]interface{}, error) {
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal([]byte(patch), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse --config-patch: %v", err)
+	}
+	if parsed == nil {
+		return nil, fmt.Errorf("--config-patch must be a JSON or YAML object")
+	}
+	return parsed, nil
+}
+
+// applyConfigPatch deep-merges the patch onto the rendered cluster config, following JSON merge patch (RFC 7386) rules:
+// objects are merged recursively, a null value removes the field, and any other value (including lists) replaces the rendered value.
+// The result must still be a valid eksctl ClusterConfig.
+func applyConfigPatch(clusterConfig []byte, patch string) ([]byte, error) {
+	patchMap, err := parseConfigPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	var configMap map[string]interface{}
+	if err := yaml.Unmarshal(clusterConfig, &configMap); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered cluster config: %v", err)
+	}
+	merged, err := yaml.Marshal(mergeConfigPatch(configMap, patchMap))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patched cluster config: %v", err)
+	}
+	var cfg eksctl_api.ClusterConfig
+	if err := yaml.UnmarshalStrict(merged, &cfg); err != nil {
+		return nil, fmt.Errorf("--config-patch does not result in a valid cluster config: %v", err)
+	}
+	return merged, nil
+}
+
+func mergeConfigPatch(target map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+	for key, patchValue := range patch {
+		if patchValue == nil {
+			delete(target, key)
+			continue
+		}
+		patchObject, patchIsObject := patchValue.(map[string]interface{})
+		targetObject, targetIsObject := target[key].(map[string]interface{})
+		if patchIsObject && targetIsObject {
+			target[key] = mergeConfigPatch(targetObject, patchObject)
+		} else if patchIsObject {
+			target[key] = mergeConfigPatch(nil, patchObject)
+		} else {
+			target[key] = patchValue
+		}
+	}
+	return target
+}
@@ -0,0 +1,48 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_applyConfigPatch(t *testing.T) {
+	rendered := []byte(`apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: test
+  region: us-west-2
+  version: "1.32"
+managedNodeGroups:
+- name: ng-1
+  instanceTypes: ["m5.large"]
+`)
+	patched, err := applyConfigPatch(rendered, `{"metadata": {"tags": {"team": "node"}, "version": "1.33"}, "managedNodeGroups": [{"name": "ng-2"}]}`)
+	assert.NoError(t, err)
+	var result map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal(patched, &result))
+	assert.Equal(t, map[string]interface{}{
+		"name":    "test",
+		"region":  "us-west-2",
+		"version": "1.33",
+		"tags":    map[string]interface{}{"team": "node"},
+	}, result["metadata"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "ng-2"}}, result["managedNodeGroups"])
+
+	patched, err = applyConfigPatch(rendered, "managedNodeGroups: null\n")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(patched), "managedNodeGroups")
+
+	_, err = applyConfigPatch(rendered, `{"metadata": {"notAField": true}}`)
+	assert.ErrorContains(t, err, "does not result in a valid cluster config")
+
+	_, err = applyConfigPatch(rendered, `[1, 2]`)
+	assert.ErrorContains(t, err, "failed to parse --config-patch")
+}
+
+func Test_RenderClusterConfig_createError(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Tags: []string{"owner"}, ConfigPatch: "metadata:\n  region: us-east-1\n"}}
+	_, err := d.RenderClusterConfig()
+	assert.ErrorContains(t, err, "failed to create ClusterConfig")
+}
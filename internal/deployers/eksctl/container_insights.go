@@ -0,0 +1,69 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+const (
+	// containerInsightsAddon runs the CloudWatch agent and Fluent Bit with Container Insights enabled
+	containerInsightsAddon           = "amazon-cloudwatch-observability"
+	containerInsightsPolicy          = "CloudWatchAgentServerPolicy"
+	containerInsightsNamespace       = "ContainerInsights"
+	containerInsightsMetricsInterval = 30 * time.Second
+	containerInsightsMetricsTimeout  = 15 * time.Minute
+)
+
+func (d *deployer) verifyContainerInsights() error {
+	if !d.ContainerInsights {
+		return nil
+	}
+	if !d.WithOIDC {
+		return fmt.Errorf("--enable-container-insights requires --with-oidc, for the CloudWatch agent's IAM role")
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--enable-container-insights requires --deploy-target=cluster, addons are created with the cluster")
+	}
+	return nil
+}
+
+// containerInsightsAddonConfig returns the CloudWatch observability addon, with an IRSA role for the CloudWatch agent
+func (d *deployer) containerInsightsAddonConfig() *eksctl_api.Addon {
+	return &eksctl_api.Addon{
+		Name: containerInsightsAddon,
+		AttachPolicyARNs: []string{
+			fmt.Sprintf("arn:%s:iam::aws:policy/%s", eksctl_api.Partitions.ForRegion(d.region()), containerInsightsPolicy),
+		},
+	}
+}
+
+// waitForContainerInsightsMetrics waits for the cluster's first Container Insights metrics to reach CloudWatch
+func (d *deployer) waitForContainerInsightsMetrics(ctx context.Context) error {
+	klog.Infof("Waiting up to %v for Container Insights metrics for cluster %s", containerInsightsMetricsTimeout, d.clusterName)
+	err := wait.PollUntilContextTimeout(ctx, containerInsightsMetricsInterval, containerInsightsMetricsTimeout, true, func(ctx context.Context) (bool, error) {
+		out, err := d.cwClient.ListMetrics(ctx, &cloudwatch.ListMetricsInput{
+			Namespace: aws.String(containerInsightsNamespace),
+			Dimensions: []cwtypes.DimensionFilter{
+				{Name: aws.String("ClusterName"), Value: aws.String(d.clusterName)},
+			},
+		}, d.cloudwatchRegion)
+		if err != nil {
+			klog.Warningf("failed to list Container Insights metrics: %v", err)
+			return false, nil
+		}
+		klog.Infof("Found %d Container Insights metrics for cluster %s", len(out.Metrics), d.clusterName)
+		return len(out.Metrics) > 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("no Container Insights metrics for cluster %s reached CloudWatch: %v", d.clusterName, err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyContainerInsights(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ContainerInsights: true, DeployTarget: "cluster"}}
+	assert.ErrorContains(t, d.verifyContainerInsights(), "requires --with-oidc")
+
+	d.WithOIDC = true
+	assert.NoError(t, d.verifyContainerInsights())
+
+	d.DeployTarget = "nodegroup"
+	assert.ErrorContains(t, d.verifyContainerInsights(), "requires --deploy-target=cluster")
+}
+
+func Test_CreateClusterConfig_containerInsights(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Region: "cn-north-1", WithOIDC: true, ContainerInsights: true}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Addons, 1)
+	assert.Equal(t, "amazon-cloudwatch-observability", cfg.Addons[0].Name)
+	assert.Equal(t, []string{"arn:aws-cn:iam::aws:policy/CloudWatchAgentServerPolicy"}, cfg.Addons[0].AttachPolicyARNs)
+}
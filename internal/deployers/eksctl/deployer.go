@@ -1,18 +1,26 @@
 package eksctl
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/aws/aws-k8s-tester/internal"
 	"github.com/aws/aws-k8s-tester/internal/awssdk"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/urfave/sflags/gen/gpflag"
 	"github.com/spf13/pflag"
 	"k8s.io/klog"
+	"sigs.k8s.io/kubetest2/pkg/artifacts"
 	"sigs.k8s.io/kubetest2/pkg/types"
 	"sigs.k8s.io/yaml"
 )
@@ -20,31 +28,92 @@ import (
 // DeployerName is the name of the deployer
 const DeployerName = "eksctl"
 
+// cloudformationAPI is the part of the CloudFormation client that the deployer uses, so that tests can fake it
+type cloudformationAPI interface {
+	cloudformation.DescribeStacksAPIClient
+	cloudformation.DescribeStackEventsAPIClient
+	cloudformation.ListStacksAPIClient
+	DeleteStack(ctx context.Context, params *cloudformation.DeleteStackInput, optFns ...func(*cloudformation.Options)) (*cloudformation.DeleteStackOutput, error)
+}
+
 type deployer struct {
 	// generic parts
 	commonOptions types.Options
 	*UpOptions
 	awsConfig      aws.Config
 	eksClient      *eks.Client
+	ec2Client      *ec2.Client
+	ssmClient      *ssm.Client
+	cfnClient      cloudformationAPI
+	cwClient       *cloudwatch.Client
+	kmsClient      *kms.Client
 	KubeconfigPath string `flag:"kubeconfig" desc:"Path to kubeconfig"`
+	// flags are the deployer's flags, to tell which were set on the command line
+	flags *pflag.FlagSet
 	// ClusterName is the effective cluster name (from flag or RunID)
 	clusterName string
+	// nodeArchitecture is the CPU architecture of the requested instance types, if known
+	nodeArchitecture string
+	// nodegroupTags are the rendered tags from the --nodegroup-tags-file
+	nodegroupTags map[string]string
+	// nodegroupSpecs are the nodegroups from the --nodegroups-file
+	nodegroupSpecs []nodegroupSpec
+	// maxPodsPerNode is the max pods for --prefix-delegation, zero to keep eksctl's default
+	maxPodsPerNode int
+	// bottlerocketSettingsDoc holds the settings from the --bottlerocket-settings-file
+	bottlerocketSettingsDoc map[string]interface{}
+	// addonConfigurationValues are the JSON configuration values of the --addons, from the --addon-configuration-values-file
+	addonConfigurationValues map[string]string
+	// subnetZones are the availability zones of the --private-subnet-ids and --public-subnet-ids
+	subnetZones map[string]string
+	// existingCluster is the cluster a custom AL2023 AMI's nodegroup is added to, for its nodeadm config
+	existingCluster *nodeadmCluster
 }
 
 // NewDeployer implements deployer.New for EKS using eksctl
 func NewDeployer(opts types.Options) (types.Deployer, *pflag.FlagSet) {
-	// create a deployer object and set fields that are not flag controlled
+	// create a deployer object and set fields that are not flag controlled, along with flag defaults
 	awsConfig := awssdk.NewConfig()
 	d := &deployer{
 		commonOptions: opts,
 		awsConfig:     awsConfig,
 		eksClient:     eks.NewFromConfig(awsConfig),
+		ec2Client:     ec2.NewFromConfig(awsConfig),
+		ssmClient:     ssm.NewFromConfig(awsConfig),
+		cfnClient:     cloudformation.NewFromConfig(awsConfig),
+		cwClient:      cloudwatch.NewFromConfig(awsConfig),
+		kmsClient:     kms.NewFromConfig(awsConfig),
+		UpOptions: &UpOptions{
+			EksctlVerbosity:      defaultEksctlVerbosity,
+			EndpointPublicAccess: true,
+		},
 	}
 	// register flags and return
-	return d, bindFlags(d)
+	d.flags = bindFlags(d)
+	return d, d.flags
+}
+
+// flagChanged reports whether the flag was set on the command line, even if to its zero value
+func (d *deployer) flagChanged(name string) bool {
+	return d.flags != nil && d.flags.Changed(name)
 }
 
+// DumpClusterLogs writes a kubectl cluster-info dump, the nodes' kubelet and containerd journals, and the events of the
+// cluster's eksctl stacks to the artifacts directory. Failures are logged rather than returned, since the logs are best effort.
 func (d *deployer) DumpClusterLogs() error {
+	d.initClusterName()
+	ctx := context.TODO()
+	if err := d.dumpClusterInfo(ctx); err != nil {
+		klog.Warningf("failed to dump cluster info: %v", err)
+	}
+	if d.AutoMode {
+		klog.Infof("Auto Mode nodes can't be reached with Run Command, node journals will not be dumped")
+	} else if err := d.dumpNodeJournals(ctx); err != nil {
+		klog.Warningf("failed to dump node journals: %v", err)
+	}
+	if _, err := d.dumpStackEvents(filepath.Join(artifacts.BaseDir(), stackEventsDir)); err != nil {
+		klog.Warningf("failed to dump CloudFormation stack events: %v", err)
+	}
 	return nil
 }
 
@@ -75,6 +144,7 @@ func bindFlags(d *deployer) *pflag.FlagSet {
 // 1. config file
 // 2. --cluster-name flag
 // 3. RunID of the kubetest
+// Unless it comes from the config file, the name is decorated with --cluster-name-prefix and --cluster-name-suffix.
 func (d *deployer) initClusterName() {
 	// First priority: config file if provided
 	if d.UpOptions.ConfigFile != "" {
@@ -95,6 +165,23 @@ func (d *deployer) initClusterName() {
 		d.clusterName = d.commonOptions.RunID()
 		klog.V(2).Infof("Using RunID for cluster name: %s", d.clusterName)
 	}
+	if d.UpOptions.ClusterNamePrefix != "" {
+		d.clusterName = d.UpOptions.ClusterNamePrefix + "-" + d.clusterName
+	}
+	if d.UpOptions.ClusterNameSuffix != "" {
+		d.clusterName = d.clusterName + "-" + d.UpOptions.ClusterNameSuffix
+	}
+}
+
+// clusterNamePattern is the format EKS accepts for cluster names
+var clusterNamePattern = regexp.MustCompile(`^[0-9A-Za-z][A-Za-z0-9\-_]{0,99}$`)
+
+// verifyClusterName checks that the effective cluster name, including any prefix and suffix, is accepted by EKS
+func (d *deployer) verifyClusterName() error {
+	if !clusterNamePattern.MatchString(d.clusterName) {
+		return fmt.Errorf("cluster name %q must be 1-100 letters, digits, '-', or '_', starting with a letter or digit", d.clusterName)
+	}
+	return nil
 }
 
 // parseClusterNameFromConfig extracts the cluster name from an eksctl config file
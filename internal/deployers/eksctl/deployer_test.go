@@ -0,0 +1,26 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_initClusterName(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "1234", ClusterNamePrefix: "ci", ClusterNameSuffix: "pr123"}}
+	d.initClusterName()
+	assert.Equal(t, "ci-1234-pr123", d.clusterName)
+	assert.NoError(t, d.verifyClusterName())
+
+	d = &deployer{UpOptions: &UpOptions{ClusterName: "1234", ClusterNamePrefix: "-ci"}}
+	d.initClusterName()
+	assert.ErrorContains(t, d.verifyClusterName(), "must be 1-100 letters")
+}
+
+func Test_renderEksctlArgs(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", EksctlVerbosity: defaultEksctlVerbosity}}
+	assert.Equal(t, []string{"create", "cluster", "--config-file", "config.yaml", "--verbose=3"}, d.renderEksctlArgs("config.yaml"))
+
+	d.NodegroupParallelism = 1
+	assert.Equal(t, []string{"create", "cluster", "--config-file", "config.yaml", "--verbose=3", "--nodegroup-parallelism=1"}, d.renderEksctlArgs("config.yaml"))
+}
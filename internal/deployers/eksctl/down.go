@@ -1,6 +1,8 @@
 package eksctl
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-k8s-tester/internal/util"
@@ -10,24 +12,62 @@ import (
 func (d *deployer) Down() error {
 	d.initClusterName()
 
-	var err error
+	ctx, cancel := contextWithOptionalTimeout(context.Background(), d.DeleteTimeout)
+	defer cancel()
 
+	var deleteErr, kmsErr error
+	var nodegroupNames []string
 	if d.DeployTarget == "nodegroup" {
-		klog.Infof("deleting nodegroup %s from cluster %s", d.NodegroupName, d.clusterName)
-		err = util.ExecuteCommand("eksctl", "delete", "nodegroup", "--cluster", d.clusterName, "--name", d.NodegroupName, "--drain=false", "--wait")
-		if err != nil {
-			return fmt.Errorf("failed to delete nodegroup: %v", err)
+		if d.NodegroupsFile != "" && len(d.nodegroupSpecs) == 0 {
+			if err := d.loadNodegroupsFile(); err != nil {
+				return err
+			}
+		}
+		// the --nodegroup-per-az nodegroups are named with the zone names, which Up resolved from any --availability-zone-ids
+		if len(d.AvailabilityZones) == 0 {
+			if err := d.resolveAvailabilityZones(); err != nil {
+				return err
+			}
+		}
+		nodegroupNames = d.nodegroupNames()
+		if len(nodegroupNames) == 0 {
+			return fmt.Errorf("no nodegroups to delete from cluster %s, --nodegroup-per-az requires --availability-zones or --availability-zone-ids", d.clusterName)
+		}
+		for _, nodegroupName := range nodegroupNames {
+			klog.Infof("deleting nodegroup %s from cluster %s", nodegroupName, d.clusterName)
+			err := util.ExecuteCommandContext(ctx, "eksctl", "delete", "nodegroup", "--cluster", d.clusterName, "--name", nodegroupName, "--drain=false", "--wait", d.verboseArg())
+			if err != nil {
+				deleteErr = fmt.Errorf("failed to delete nodegroup: %v", err)
+				break
+			}
+			klog.Infof("Successfully deleted nodegroup: %s from cluster: %s", nodegroupName, d.clusterName)
 		}
-		klog.Infof("Successfully deleted nodegroup: %s from cluster: %s", d.NodegroupName, d.clusterName)
 	} else if d.DeployTarget == "cluster" {
+		if len(d.FargateProfiles) > 0 {
+			d.deleteFargateProfiles(ctx)
+		}
 		klog.Infof("deleting cluster %s", d.clusterName)
-		err = util.ExecuteCommand("eksctl", "delete", "cluster", "--name", d.clusterName, "--wait", "--disable-nodegroup-eviction")
+		err := util.ExecuteCommandContext(ctx, "eksctl", "delete", "cluster", "--name", d.clusterName, "--wait", "--disable-nodegroup-eviction", d.verboseArg())
 		if err != nil {
-			return fmt.Errorf("failed to delete cluster: %v", err)
+			deleteErr = fmt.Errorf("failed to delete cluster: %v", err)
+		} else {
+			klog.Infof("Successfully deleted cluster: %s", d.clusterName)
+			if d.CreateKMSKey {
+				kmsErr = d.scheduleKMSKeyDeletion(ctx)
+			}
 		}
-		klog.Infof("Successfully deleted cluster: %s", d.clusterName)
 	} else {
 		return fmt.Errorf("Unsupported deploy target: %s, supported options: `cluster`, `nodegroup`.", d.DeployTarget)
 	}
+	// the sweep also runs after a failed delete, since that's when leftover resources are most likely
+	var sweepErr error
+	if len(d.Tags) > 0 {
+		if err := d.sweepTaggedResources(ctx, nodegroupNames); err != nil {
+			sweepErr = fmt.Errorf("failed to sweep resources tagged with --tags: %v", err)
+		}
+	}
+	if err := errors.Join(deleteErr, kmsErr, sweepErr); err != nil {
+		return d.withStackEvents(err)
+	}
 	return nil
 }
@@ -0,0 +1,27 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Down_noNodegroups(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", DeployTarget: "nodegroup", NodegroupPerAZ: true}}
+	assert.EqualError(t, d.Down(), "no nodegroups to delete from cluster test, --nodegroup-per-az requires --availability-zones or --availability-zone-ids")
+}
+
+func Test_resolveAvailabilityZones(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{AvailabilityZones: []string{"us-west-2a"}}}
+	assert.NoError(t, d.resolveAvailabilityZones())
+	assert.Equal(t, []string{"us-west-2a"}, d.AvailabilityZones)
+
+	d.AvailabilityZoneIDs = []string{"usw2-az1"}
+	assert.EqualError(t, d.resolveAvailabilityZones(), "--availability-zones and --availability-zone-ids are mutually exclusive")
+}
+
+func Test_Down_nodegroupsFile(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", DeployTarget: "nodegroup", NodegroupsFile: "testdata/missing.yaml"}}
+	// the nodegroups to delete come from the file, even when Up didn't run in this process
+	assert.ErrorContains(t, d.Down(), "failed to read --nodegroups-file")
+}
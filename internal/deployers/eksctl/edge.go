@@ -0,0 +1,51 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"k8s.io/klog"
+)
+
+// verifyEdgePlacement checks the flags that place the nodegroup on a Local Zone or an Outpost.
+// eksctl only supports self-managed nodegroups in either location.
+func (d *deployer) verifyEdgePlacement() error {
+	if len(d.NodeSubnetIDs) > 0 && (len(d.AvailabilityZones) > 0 || d.NodegroupPerAZ) {
+		return fmt.Errorf("--node-subnet-ids cannot be used with --availability-zones, --availability-zone-ids, or --nodegroup-per-az")
+	}
+	if d.LocalZone == "" && d.OutpostARN == "" {
+		return nil
+	}
+	if d.LocalZone != "" && d.OutpostARN != "" {
+		return fmt.Errorf("--local-zone and --outpost-arn are mutually exclusive")
+	}
+	if !d.UseUnmanagedNodegroup {
+		return fmt.Errorf("--local-zone and --outpost-arn require --unmanaged-nodegroup")
+	}
+	if d.OutpostARN != "" {
+		parsed, err := arn.Parse(d.OutpostARN)
+		if err != nil || parsed.Service != "outposts" {
+			return fmt.Errorf("--outpost-arn is not a valid Outpost ARN: %s", d.OutpostARN)
+		}
+		return nil
+	}
+	if len(d.NodeSubnetIDs) == 0 {
+		return fmt.Errorf("--local-zone requires --node-subnet-ids in that zone")
+	}
+	out, err := d.ec2Client.DescribeSubnets(context.TODO(), &ec2.DescribeSubnetsInput{
+		SubnetIds: d.NodeSubnetIDs,
+	}, d.ec2Region)
+	if err != nil {
+		return fmt.Errorf("failed to describe subnets %v: %v", d.NodeSubnetIDs, err)
+	}
+	for _, subnet := range out.Subnets {
+		if aws.ToString(subnet.AvailabilityZone) != d.LocalZone {
+			return fmt.Errorf("subnet %s is in %s, not --local-zone %s", aws.ToString(subnet.SubnetId), aws.ToString(subnet.AvailabilityZone), d.LocalZone)
+		}
+	}
+	klog.Infof("Placing nodegroup in Local Zone %s on subnets %v", d.LocalZone, d.NodeSubnetIDs)
+	return nil
+}
@@ -0,0 +1,65 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	efaResourceName       = corev1.ResourceName("vpc.amazonaws.com/efa")
+	efaAllocatableTimeout = 10 * time.Minute
+)
+
+// verifyEFA checks that the --efa-enabled nodegroups can be launched into a cluster placement group,
+// which holds instances of EFA-capable instance types in a single availability zone
+func (d *deployer) verifyEFA() error {
+	if !d.EFAEnabled {
+		if d.EFAPlacementGroup != "" {
+			return fmt.Errorf("--efa-placement-group requires --efa-enabled")
+		}
+		return nil
+	}
+	if !d.NodegroupPerAZ && len(d.AvailabilityZones) != 1 && len(d.NodeSubnetIDs) != 1 {
+		return fmt.Errorf("--efa-enabled requires the nodegroup to be in one availability zone, set a single zone in --availability-zones or --node-subnet-ids, or use --nodegroup-per-az")
+	}
+	if len(d.InstanceTypes) == 0 {
+		return fmt.Errorf("--efa-enabled requires --instance-types that support EFA")
+	}
+	out, err := d.ec2Client.DescribeInstanceTypes(context.TODO(), &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: toInstanceTypes(d.InstanceTypes),
+	}, d.ec2Region)
+	if err != nil {
+		return fmt.Errorf("failed to describe instance types %v: %v", d.InstanceTypes, err)
+	}
+	var nonEFAInstanceTypes []string
+	for _, instanceType := range out.InstanceTypes {
+		if instanceType.NetworkInfo == nil || !aws.ToBool(instanceType.NetworkInfo.EfaSupported) {
+			nonEFAInstanceTypes = append(nonEFAInstanceTypes, string(instanceType.InstanceType))
+		}
+	}
+	if len(nonEFAInstanceTypes) > 0 {
+		return fmt.Errorf("--efa-enabled requires instance types that support EFA: %v", nonEFAInstanceTypes)
+	}
+	return nil
+}
+
+// setEFAPlacement renders the --efa-placement-group onto an EFA nodegroup.
+// Without it, eksctl creates a cluster placement group in each EFA nodegroup's stack.
+func (d *deployer) setEFAPlacement(ng *eksctl_api.NodeGroupBase) {
+	if !d.EFAEnabled || d.EFAPlacementGroup == "" {
+		return
+	}
+	ng.Placement = &eksctl_api.Placement{GroupName: d.EFAPlacementGroup}
+}
+
+// waitForEFAAllocatable waits for every node to advertise EFA devices. eksctl installs the EFA device plugin with EFA nodegroups,
+// so this catches nodes whose EFA interfaces weren't attached or whose device plugin isn't running.
+func (d *deployer) waitForEFAAllocatable(ctx context.Context) error {
+	return d.waitForAllocatable(ctx, efaResourceName, efaAllocatableTimeout)
+}
@@ -0,0 +1,32 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyEFA(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{EFAPlacementGroup: "efa-pg"}}
+	assert.ErrorContains(t, d.verifyEFA(), "--efa-placement-group requires --efa-enabled")
+
+	d.EFAEnabled = true
+	d.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+	assert.ErrorContains(t, d.verifyEFA(), "--efa-enabled requires the nodegroup to be in one availability zone")
+
+	d.NodegroupPerAZ = true
+	assert.ErrorContains(t, d.verifyEFA(), "--efa-enabled requires --instance-types that support EFA")
+}
+
+func Test_CreateClusterConfig_efaPlacementGroup(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Nodes: 2, EFAEnabled: true, EFAPlacementGroup: "efa-pg", AvailabilityZones: []string{"us-west-2a"}}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.True(t, *cfg.ManagedNodeGroups[0].EFAEnabled)
+	assert.Equal(t, "efa-pg", cfg.ManagedNodeGroups[0].Placement.GroupName)
+
+	d.EFAPlacementGroup = ""
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, cfg.ManagedNodeGroups[0].Placement)
+}
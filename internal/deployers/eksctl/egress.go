@@ -0,0 +1,141 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/klog"
+)
+
+// imagePullEndpointServices are the VPC endpoints that let private nodes pull images from ECR without a NAT
+var imagePullEndpointServices = []string{"ecr.api", "ecr.dkr", "s3"}
+
+// verifyPrivateEgress checks that the --node-subnet-ids of a --private-networking nodegroup can reach image registries.
+// Each subnet needs a default route through a NAT (or transit gateway, or NAT instance),
+// otherwise the VPC needs ECR and S3 endpoints; without either, nodes fail much later with image pull timeouts.
+func (d *deployer) verifyPrivateEgress() error {
+	if !d.PrivateNetworking || len(d.NodeSubnetIDs) == 0 {
+		return nil
+	}
+	subnets, err := d.ec2Client.DescribeSubnets(context.TODO(), &ec2.DescribeSubnetsInput{
+		SubnetIds: d.NodeSubnetIDs,
+	}, d.ec2Region)
+	if err != nil {
+		return fmt.Errorf("failed to describe subnets %v: %v", d.NodeSubnetIDs, err)
+	}
+	var vpcIDs []string
+	subnetVPCs := make(map[string]string)
+	for _, subnet := range subnets.Subnets {
+		vpcID := aws.ToString(subnet.VpcId)
+		if !slices.Contains(vpcIDs, vpcID) {
+			vpcIDs = append(vpcIDs, vpcID)
+		}
+		subnetVPCs[aws.ToString(subnet.SubnetId)] = vpcID
+	}
+	var routeTables []ec2types.RouteTable
+	paginator := ec2.NewDescribeRouteTablesPaginator(d.ec2Client, &ec2.DescribeRouteTablesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: vpcIDs,
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO(), d.ec2Region)
+		if err != nil {
+			return fmt.Errorf("failed to describe route tables for %v: %v", vpcIDs, err)
+		}
+		routeTables = append(routeTables, page.RouteTables...)
+	}
+	var subnetsWithoutEgress []string
+	for _, subnetID := range d.NodeSubnetIDs {
+		routeTable := subnetRouteTable(subnetID, subnetVPCs[subnetID], routeTables)
+		if routeTable == nil || !hasDefaultEgressRoute(*routeTable) {
+			subnetsWithoutEgress = append(subnetsWithoutEgress, subnetID)
+		}
+	}
+	if len(subnetsWithoutEgress) == 0 {
+		return nil
+	}
+	missingEndpoints, err := d.missingImagePullEndpoints(vpcIDs)
+	if err != nil {
+		return err
+	}
+	if len(missingEndpoints) > 0 {
+		return fmt.Errorf("--private-networking subnets %v have no default route through a NAT or transit gateway, and their VPC has no %v endpoints; nodes would not be able to pull images", subnetsWithoutEgress, missingEndpoints)
+	}
+	klog.Warningf("--private-networking subnets %v have no default route through a NAT or transit gateway; nodes can only pull images from ECR through VPC endpoints", subnetsWithoutEgress)
+	return nil
+}
+
+// subnetRouteTable returns the route table explicitly associated with the subnet, falling back to the VPC's main route table
+func subnetRouteTable(subnetID string, vpcID string, routeTables []ec2types.RouteTable) *ec2types.RouteTable {
+	var main *ec2types.RouteTable
+	for i, routeTable := range routeTables {
+		for _, association := range routeTable.Associations {
+			if aws.ToString(association.SubnetId) == subnetID {
+				return &routeTables[i]
+			}
+			if aws.ToBool(association.Main) && aws.ToString(routeTable.VpcId) == vpcID {
+				main = &routeTables[i]
+			}
+		}
+	}
+	return main
+}
+
+// hasDefaultEgressRoute reports whether the route table sends 0.0.0.0/0 somewhere that can reach the internet without a public IP.
+// An internet gateway doesn't count, because nodes on private networking don't get public IPs.
+func hasDefaultEgressRoute(routeTable ec2types.RouteTable) bool {
+	for _, route := range routeTable.Routes {
+		if aws.ToString(route.DestinationCidrBlock) != "0.0.0.0/0" || route.State == ec2types.RouteStateBlackhole {
+			continue
+		}
+		if route.NatGatewayId != nil || route.TransitGatewayId != nil || route.InstanceId != nil || route.NetworkInterfaceId != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// missingImagePullEndpoints returns the imagePullEndpointServices that the VPCs don't have endpoints for
+func (d *deployer) missingImagePullEndpoints(vpcIDs []string) ([]string, error) {
+	var serviceNames []string
+	for _, service := range imagePullEndpointServices {
+		serviceNames = append(serviceNames, fmt.Sprintf("com.amazonaws.%s.%s", d.region(), service))
+	}
+	found := make(map[string]bool)
+	paginator := ec2.NewDescribeVpcEndpointsPaginator(d.ec2Client, &ec2.DescribeVpcEndpointsInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: vpcIDs,
+			},
+			{
+				Name:   aws.String("service-name"),
+				Values: serviceNames,
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO(), d.ec2Region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe VPC endpoints for %v: %v", vpcIDs, err)
+		}
+		for _, endpoint := range page.VpcEndpoints {
+			found[aws.ToString(endpoint.ServiceName)] = true
+		}
+	}
+	var missing []string
+	for i, serviceName := range serviceNames {
+		if !found[serviceName] {
+			missing = append(missing, imagePullEndpointServices[i])
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,66 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_subnetRouteTable(t *testing.T) {
+	routeTables := []ec2types.RouteTable{
+		{
+			RouteTableId: aws.String("rtb-main"),
+			VpcId:        aws.String("vpc-1"),
+			Associations: []ec2types.RouteTableAssociation{{Main: aws.Bool(true)}},
+		},
+		{
+			RouteTableId: aws.String("rtb-private"),
+			VpcId:        aws.String("vpc-1"),
+			Associations: []ec2types.RouteTableAssociation{{SubnetId: aws.String("subnet-private")}},
+		},
+	}
+	assert.Equal(t, "rtb-private", aws.ToString(subnetRouteTable("subnet-private", "vpc-1", routeTables).RouteTableId))
+	assert.Equal(t, "rtb-main", aws.ToString(subnetRouteTable("subnet-other", "vpc-1", routeTables).RouteTableId))
+	assert.Nil(t, subnetRouteTable("subnet-other", "vpc-2", routeTables))
+}
+
+func Test_hasDefaultEgressRoute(t *testing.T) {
+	cases := []struct {
+		name   string
+		routes []ec2types.Route
+		want   bool
+	}{
+		{
+			name:   "local only",
+			routes: []ec2types.Route{{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local")}},
+			want:   false,
+		},
+		{
+			name:   "internet gateway",
+			routes: []ec2types.Route{{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("igw-1")}},
+			want:   false,
+		},
+		{
+			name:   "nat gateway",
+			routes: []ec2types.Route{{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1"), State: ec2types.RouteStateActive}},
+			want:   true,
+		},
+		{
+			name:   "blackholed nat gateway",
+			routes: []ec2types.Route{{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1"), State: ec2types.RouteStateBlackhole}},
+			want:   false,
+		},
+		{
+			name:   "transit gateway",
+			routes: []ec2types.Route{{DestinationCidrBlock: aws.String("0.0.0.0/0"), TransitGatewayId: aws.String("tgw-1")}},
+			want:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, hasDefaultEgressRoute(ec2types.RouteTable{Routes: c.routes}))
+		})
+	}
+}
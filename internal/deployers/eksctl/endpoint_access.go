@@ -0,0 +1,99 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/klog"
+)
+
+// customEndpointAccess reports whether the API server endpoint access differs from EKS's default of public only
+func (d *deployer) customEndpointAccess() bool {
+	return d.EndpointPrivateAccess || !d.EndpointPublicAccess || len(d.PublicAccessCIDRs) > 0
+}
+
+func (d *deployer) verifyEndpointAccess() error {
+	if !d.customEndpointAccess() {
+		return nil
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--endpoint-private-access, --endpoint-public-access, and --public-access-cidrs require --deploy-target=cluster")
+	}
+	if !d.EndpointPrivateAccess && !d.EndpointPublicAccess {
+		return fmt.Errorf("--endpoint-private-access or --endpoint-public-access must be enabled, or the API server is unreachable")
+	}
+	if len(d.PublicAccessCIDRs) > 0 && !d.EndpointPublicAccess {
+		return fmt.Errorf("--public-access-cidrs requires --endpoint-public-access")
+	}
+	for _, cidr := range d.PublicAccessCIDRs {
+		ip, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("--public-access-cidrs has an invalid CIDR: %v", err)
+		}
+		if ip.To4() == nil {
+			return fmt.Errorf("--public-access-cidrs must be IPv4 CIDRs: %s", cidr)
+		}
+		// EKS reports the CIDRs as network addresses
+		if !ip.Equal(network.IP) {
+			return fmt.Errorf("--public-access-cidrs must be network addresses, did you mean %s?", network)
+		}
+	}
+	if !d.EndpointPublicAccess {
+		klog.Warningf("The API server will only be reachable from within the cluster's VPC, or networks connected to it")
+	}
+	return nil
+}
+
+// setEndpointAccess renders the API server endpoint access, leaving eksctl's default of public only if the flags keep it
+func (d *deployer) setEndpointAccess(cfg *eksctl_api.ClusterConfig) {
+	if !d.customEndpointAccess() {
+		return
+	}
+	cfg.VPC.ClusterEndpoints = &eksctl_api.ClusterEndpoints{
+		PrivateAccess: aws.Bool(d.EndpointPrivateAccess),
+		PublicAccess:  aws.Bool(d.EndpointPublicAccess),
+	}
+	cfg.VPC.PublicAccessCIDRs = d.PublicAccessCIDRs
+}
+
+// checkEndpointAccess checks that EKS applied the endpoint access flags, and that the API server is reachable through them,
+// since a private-only endpoint, or public access CIDRs that leave out this host, make the cluster unusable from here
+func (d *deployer) checkEndpointAccess(ctx context.Context) error {
+	if !d.customEndpointAccess() {
+		return nil
+	}
+	out, err := d.eksClient.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(d.clusterName),
+	}, d.eksRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe cluster %s: %v", d.clusterName, err)
+	}
+	vpcConfig := out.Cluster.ResourcesVpcConfig
+	if vpcConfig == nil || vpcConfig.EndpointPrivateAccess != d.EndpointPrivateAccess || vpcConfig.EndpointPublicAccess != d.EndpointPublicAccess {
+		return fmt.Errorf("cluster %s endpoint access is not private=%t, public=%t", d.clusterName, d.EndpointPrivateAccess, d.EndpointPublicAccess)
+	}
+	for _, cidr := range d.PublicAccessCIDRs {
+		if !slices.Contains(vpcConfig.PublicAccessCidrs, cidr) {
+			return fmt.Errorf("cluster %s public access CIDRs %v do not include %s", d.clusterName, vpcConfig.PublicAccessCidrs, cidr)
+		}
+	}
+	route := "public endpoint"
+	if !d.EndpointPublicAccess {
+		route = "private endpoint, from within the cluster's VPC"
+	}
+	clientset, err := d.kubernetesClient()
+	if err != nil {
+		return err
+	}
+	serverVersion, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("failed to reach the API server of cluster %s through its %s: %v", d.clusterName, route, err)
+	}
+	klog.Infof("Reached API server %s of cluster %s through its %s", serverVersion.GitVersion, d.clusterName, route)
+	return nil
+}
@@ -0,0 +1,52 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyEndpointAccess(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", EndpointPublicAccess: true}}
+	assert.NoError(t, d.verifyEndpointAccess())
+
+	d.EndpointPublicAccess = false
+	assert.ErrorContains(t, d.verifyEndpointAccess(), "--endpoint-private-access or --endpoint-public-access must be enabled")
+
+	d.EndpointPrivateAccess = true
+	assert.NoError(t, d.verifyEndpointAccess())
+	d.PublicAccessCIDRs = []string{"203.0.113.0/24"}
+	assert.ErrorContains(t, d.verifyEndpointAccess(), "--public-access-cidrs requires --endpoint-public-access")
+
+	d.EndpointPublicAccess = true
+	assert.NoError(t, d.verifyEndpointAccess())
+	d.PublicAccessCIDRs = []string{"203.0.113.7/24"}
+	assert.ErrorContains(t, d.verifyEndpointAccess(), "did you mean 203.0.113.0/24")
+	d.PublicAccessCIDRs = []string{"2001:db8::/32"}
+	assert.ErrorContains(t, d.verifyEndpointAccess(), "--public-access-cidrs must be IPv4 CIDRs")
+
+	d.PublicAccessCIDRs = nil
+	d.DeployTarget = "nodegroup"
+	assert.ErrorContains(t, d.verifyEndpointAccess(), "require --deploy-target=cluster")
+}
+
+func Test_CreateClusterConfig_endpointAccess(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", EndpointPublicAccess: true}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, cfg.VPC.ClusterEndpoints)
+
+	d.EndpointPrivateAccess = true
+	d.PublicAccessCIDRs = []string{"203.0.113.0/24"}
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.True(t, *cfg.VPC.ClusterEndpoints.PrivateAccess)
+	assert.True(t, *cfg.VPC.ClusterEndpoints.PublicAccess)
+	assert.Equal(t, []string{"203.0.113.0/24"}, cfg.VPC.PublicAccessCIDRs)
+
+	d.EndpointPublicAccess = false
+	d.PublicAccessCIDRs = nil
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.False(t, *cfg.VPC.ClusterEndpoints.PublicAccess)
+}
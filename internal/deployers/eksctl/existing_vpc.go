@@ -0,0 +1,88 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// minClusterSubnetZones is the number of availability zones EKS requires the cluster's subnets to span
+const minClusterSubnetZones = 2
+
+// verifyExistingVPC checks that the --private-subnet-ids and --public-subnet-ids are in the --vpc-id and span enough availability zones,
+// and looks up each subnet's zone for the cluster config
+func (d *deployer) verifyExistingVPC() error {
+	subnetIDs := slices.Concat(d.PrivateSubnetIDs, d.PublicSubnetIDs)
+	if d.VPCID == "" {
+		if len(subnetIDs) > 0 {
+			return fmt.Errorf("--private-subnet-ids and --public-subnet-ids require --vpc-id")
+		}
+		return nil
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--vpc-id requires --deploy-target=cluster, nodegroups are created in their cluster's VPC")
+	}
+	if d.VPCCIDR != "" {
+		return fmt.Errorf("--vpc-cidr cannot be used with --vpc-id, the existing VPC's CIDR is used")
+	}
+	if len(subnetIDs) == 0 {
+		return fmt.Errorf("--vpc-id requires --private-subnet-ids or --public-subnet-ids")
+	}
+	if d.PrivateNetworking && len(d.PrivateSubnetIDs) == 0 {
+		return fmt.Errorf("--private-networking with --vpc-id requires --private-subnet-ids")
+	}
+	out, err := d.ec2Client.DescribeSubnets(context.TODO(), &ec2.DescribeSubnetsInput{
+		SubnetIds: subnetIDs,
+	}, d.ec2Region)
+	if err != nil {
+		return fmt.Errorf("failed to describe subnets %v: %v", subnetIDs, err)
+	}
+	subnetZones := make(map[string]string)
+	var zones []string
+	for _, subnet := range out.Subnets {
+		subnetID := aws.ToString(subnet.SubnetId)
+		if vpcID := aws.ToString(subnet.VpcId); vpcID != d.VPCID {
+			return fmt.Errorf("subnet %s is in VPC %s, not --vpc-id %s", subnetID, vpcID, d.VPCID)
+		}
+		zone := aws.ToString(subnet.AvailabilityZone)
+		subnetZones[subnetID] = zone
+		if !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+	}
+	if len(zones) < minClusterSubnetZones {
+		return fmt.Errorf("the subnets of --vpc-id must span at least %d availability zones, found %v", minClusterSubnetZones, zones)
+	}
+	d.subnetZones = subnetZones
+	return nil
+}
+
+// setExistingVPC renders the --vpc-id and its subnets, so eksctl uses them instead of creating a VPC
+func (d *deployer) setExistingVPC(cfg *eksctl_api.ClusterConfig) {
+	if d.VPCID == "" {
+		return
+	}
+	cfg.VPC.ID = d.VPCID
+	// eksctl takes the CIDR from the existing VPC, and rejects the default CIDR if it doesn't match
+	cfg.VPC.CIDR = nil
+	cfg.VPC.Subnets = &eksctl_api.ClusterSubnets{
+		Private: d.subnetMapping(d.PrivateSubnetIDs),
+		Public:  d.subnetMapping(d.PublicSubnetIDs),
+	}
+}
+
+// subnetMapping keys the subnets by ID, along with their availability zones if known
+func (d *deployer) subnetMapping(subnetIDs []string) eksctl_api.AZSubnetMapping {
+	if len(subnetIDs) == 0 {
+		return nil
+	}
+	mapping := eksctl_api.NewAZSubnetMapping()
+	for _, subnetID := range subnetIDs {
+		mapping[subnetID] = eksctl_api.AZSubnetSpec{ID: subnetID, AZ: d.subnetZones[subnetID]}
+	}
+	return mapping
+}
@@ -0,0 +1,45 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+func Test_verifyExistingVPC(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{PrivateSubnetIDs: []string{"subnet-a"}}}
+	assert.ErrorContains(t, d.verifyExistingVPC(), "require --vpc-id")
+
+	d = &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", VPCID: "vpc-1"}}
+	assert.ErrorContains(t, d.verifyExistingVPC(), "--vpc-id requires --private-subnet-ids or --public-subnet-ids")
+
+	d.VPCCIDR = "10.0.0.0/16"
+	assert.ErrorContains(t, d.verifyExistingVPC(), "--vpc-cidr cannot be used with --vpc-id")
+
+	d = &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", VPCID: "vpc-1", PublicSubnetIDs: []string{"subnet-a"}, PrivateNetworking: true}}
+	assert.ErrorContains(t, d.verifyExistingVPC(), "--private-networking with --vpc-id requires --private-subnet-ids")
+
+	d.DeployTarget = "nodegroup"
+	assert.ErrorContains(t, d.verifyExistingVPC(), "--vpc-id requires --deploy-target=cluster")
+}
+
+func Test_CreateClusterConfig_existingVPC(t *testing.T) {
+	d := &deployer{
+		UpOptions: &UpOptions{
+			ClusterName:      "test",
+			VPCID:            "vpc-1",
+			PrivateSubnetIDs: []string{"subnet-a", "subnet-b"},
+		},
+		subnetZones: map[string]string{"subnet-a": "us-west-2a", "subnet-b": "us-west-2b"},
+	}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "vpc-1", cfg.VPC.ID)
+	assert.Nil(t, cfg.VPC.CIDR)
+	assert.Equal(t, eksctl_api.AZSubnetMapping{
+		"subnet-a": {ID: "subnet-a", AZ: "us-west-2a"},
+		"subnet-b": {ID: "subnet-b", AZ: "us-west-2b"},
+	}, cfg.VPC.Subnets.Private)
+	assert.Nil(t, cfg.VPC.Subnets.Public)
+}
@@ -0,0 +1,72 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-k8s-tester/internal/util"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog"
+)
+
+// maxFargateProfileSelectors is the most selectors EKS allows on a Fargate profile
+const maxFargateProfileSelectors = 5
+
+// parseFargateProfiles parses --fargate-profiles, each a name=namespace pair.
+// A profile named more than once selects each of its namespaces, and namespaces may use * and ? wildcards.
+func parseFargateProfiles(pairs []string) ([]*eksctl_api.FargateProfile, error) {
+	var profiles []*eksctl_api.FargateProfile
+	byName := make(map[string]*eksctl_api.FargateProfile)
+	for _, pair := range pairs {
+		name, namespace, ok := strings.Cut(pair, "=")
+		if !ok || name == "" || namespace == "" {
+			return nil, fmt.Errorf("--fargate-profiles expected name=namespace pair: %s", pair)
+		}
+		if strings.HasPrefix(name, "eks-") {
+			return nil, fmt.Errorf("--fargate-profiles name must not start with the reserved prefix 'eks-': %s", name)
+		}
+		if errs := validation.IsDNS1123Label(strings.NewReplacer("*", "a", "?", "a").Replace(namespace)); len(errs) > 0 {
+			return nil, fmt.Errorf("--fargate-profiles namespace for %s is invalid: %s", name, strings.Join(errs, "; "))
+		}
+		profile, ok := byName[name]
+		if !ok {
+			profile = &eksctl_api.FargateProfile{Name: name}
+			byName[name] = profile
+			profiles = append(profiles, profile)
+		}
+		if len(profile.Selectors) == maxFargateProfileSelectors {
+			return nil, fmt.Errorf("--fargate-profiles profile %s has more than %d namespaces", name, maxFargateProfileSelectors)
+		}
+		profile.Selectors = append(profile.Selectors, eksctl_api.FargateProfileSelector{Namespace: namespace})
+	}
+	return profiles, nil
+}
+
+func (d *deployer) verifyFargateProfiles() error {
+	if len(d.FargateProfiles) == 0 {
+		return nil
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--fargate-profiles requires --deploy-target=cluster, eksctl only creates the profiles with the cluster")
+	}
+	_, err := parseFargateProfiles(d.FargateProfiles)
+	return err
+}
+
+// deleteFargateProfiles deletes the --fargate-profiles ahead of the cluster, one at a time with eksctl.
+// Failures are logged rather than returned, since deleting the cluster also deletes any profiles left behind.
+func (d *deployer) deleteFargateProfiles(ctx context.Context) {
+	profiles, err := parseFargateProfiles(d.FargateProfiles)
+	if err != nil {
+		klog.Warningf("not deleting Fargate profiles: %v", err)
+		return
+	}
+	for _, profile := range profiles {
+		klog.Infof("deleting Fargate profile %s from cluster %s", profile.Name, d.clusterName)
+		if err := util.ExecuteCommandContext(ctx, "eksctl", "delete", "fargateprofile", "--cluster", d.clusterName, "--name", profile.Name, "--wait", d.verboseArg()); err != nil {
+			klog.Warningf("failed to delete Fargate profile %s, it will be deleted with the cluster: %v", profile.Name, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+func Test_parseFargateProfiles(t *testing.T) {
+	profiles, err := parseFargateProfiles([]string{"fp-default=default", "fp-test=e2e-*", "fp-default=kube-system"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*eksctl_api.FargateProfile{
+		{Name: "fp-default", Selectors: []eksctl_api.FargateProfileSelector{{Namespace: "default"}, {Namespace: "kube-system"}}},
+		{Name: "fp-test", Selectors: []eksctl_api.FargateProfileSelector{{Namespace: "e2e-*"}}},
+	}, profiles)
+
+	_, err = parseFargateProfiles([]string{"fp-default"})
+	assert.ErrorContains(t, err, "expected name=namespace pair")
+	_, err = parseFargateProfiles([]string{"eks-default=default"})
+	assert.ErrorContains(t, err, "reserved prefix 'eks-'")
+	_, err = parseFargateProfiles([]string{"fp=Default"})
+	assert.ErrorContains(t, err, "namespace for fp is invalid")
+	_, err = parseFargateProfiles([]string{"fp=a", "fp=b", "fp=c", "fp=d", "fp=e", "fp=f"})
+	assert.ErrorContains(t, err, "profile fp has more than 5 namespaces")
+}
+
+func Test_verifyFargateProfiles(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", DeployTarget: "nodegroup", FargateProfiles: []string{"fp=default"}}}
+	assert.ErrorContains(t, d.verifyFargateProfiles(), "requires --deploy-target=cluster")
+
+	d.DeployTarget = "cluster"
+	assert.NoError(t, d.verifyFargateProfiles())
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "fp", cfg.FargateProfiles[0].Name)
+}
@@ -0,0 +1,100 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-k8s-tester/internal/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	nvidiaDevicePluginManifest = "https://raw.githubusercontent.com/NVIDIA/k8s-device-plugin/v0.17.1/deployments/static/nvidia-device-plugin.yml"
+	nvidiaGPUResourceName      = corev1.ResourceName("nvidia.com/gpu")
+	gpuAllocatableTimeout      = 10 * time.Minute
+)
+
+// verifyGPUInstanceTypes checks that every requested instance type has an NVIDIA GPU
+func (d *deployer) verifyGPUInstanceTypes() error {
+	if len(d.InstanceTypes) == 0 {
+		return fmt.Errorf("--install-gpu-device-plugin requires --instance-types with NVIDIA GPUs")
+	}
+	nonGPUInstanceTypes, err := d.nonGPUInstanceTypes()
+	if err != nil {
+		return err
+	}
+	if len(nonGPUInstanceTypes) > 0 {
+		return fmt.Errorf("--install-gpu-device-plugin requires instance types with NVIDIA GPUs: %v", nonGPUInstanceTypes)
+	}
+	return nil
+}
+
+// detectGPUDevicePlugin turns on --install-gpu-device-plugin when every requested instance type has an NVIDIA GPU,
+// e.g. the p and g families, other than the g families with AMD GPUs or AWS accelerators
+func (d *deployer) detectGPUDevicePlugin() error {
+	if len(d.InstanceTypes) == 0 {
+		return nil
+	}
+	nonGPUInstanceTypes, err := d.nonGPUInstanceTypes()
+	if err != nil {
+		return err
+	}
+	if len(nonGPUInstanceTypes) > 0 {
+		klog.Infof("Not installing the NVIDIA device plugin, instance types %v don't have NVIDIA GPUs", nonGPUInstanceTypes)
+		return nil
+	}
+	klog.Infof("Instance types %v have NVIDIA GPUs, installing the NVIDIA device plugin after the cluster is up", d.InstanceTypes)
+	d.InstallGPUDevicePlugin = true
+	return nil
+}
+
+// nonGPUInstanceTypes returns the requested instance types that don't have an NVIDIA GPU
+func (d *deployer) nonGPUInstanceTypes() ([]string, error) {
+	out, err := d.ec2Client.DescribeInstanceTypes(context.TODO(), &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: toInstanceTypes(d.InstanceTypes),
+	}, d.ec2Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instance types %v: %v", d.InstanceTypes, err)
+	}
+	var nonGPUInstanceTypes []string
+	for _, instanceType := range out.InstanceTypes {
+		if !hasNVIDIAGPU(instanceType) {
+			nonGPUInstanceTypes = append(nonGPUInstanceTypes, string(instanceType.InstanceType))
+		}
+	}
+	return nonGPUInstanceTypes, nil
+}
+
+func toInstanceTypes(instanceTypes []string) []ec2types.InstanceType {
+	var converted []ec2types.InstanceType
+	for _, instanceType := range instanceTypes {
+		converted = append(converted, ec2types.InstanceType(instanceType))
+	}
+	return converted
+}
+
+func hasNVIDIAGPU(instanceType ec2types.InstanceTypeInfo) bool {
+	if instanceType.GpuInfo == nil {
+		return false
+	}
+	for _, gpu := range instanceType.GpuInfo.Gpus {
+		if aws.ToString(gpu.Manufacturer) == "NVIDIA" {
+			return true
+		}
+	}
+	return false
+}
+
+// installGPUDevicePlugin applies the NVIDIA device plugin daemonset and waits for every node to report allocatable GPUs
+func (d *deployer) installGPUDevicePlugin(ctx context.Context) error {
+	klog.Infof("Installing NVIDIA device plugin: %s", nvidiaDevicePluginManifest)
+	if err := util.ExecuteCommandContext(ctx, "kubectl", "--kubeconfig", d.KubeconfigPath, "apply", "-f", nvidiaDevicePluginManifest); err != nil {
+		return fmt.Errorf("failed to apply NVIDIA device plugin: %v", err)
+	}
+	return d.waitForAllocatable(ctx, nvidiaGPUResourceName, gpuAllocatableTimeout)
+}
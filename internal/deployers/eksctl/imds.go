@@ -0,0 +1,91 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/klog"
+)
+
+const (
+	minIMDSHopLimit = 1
+	maxIMDSHopLimit = 64
+)
+
+func (d *deployer) verifyIMDSHopLimit() error {
+	if d.IMDSHopLimit == 0 {
+		return nil
+	}
+	if d.IMDSHopLimit < minIMDSHopLimit || d.IMDSHopLimit > maxIMDSHopLimit {
+		return fmt.Errorf("--imds-hop-limit must be between %d and %d", minIMDSHopLimit, maxIMDSHopLimit)
+	}
+	return nil
+}
+
+// applyIMDSHopLimit sets the instance metadata PUT response hop limit on the nodegroup's instances.
+// The eksctl ClusterConfig has no field for this, so the instances are modified after they're created.
+// Instances that replace these later (for example, after a scale up) will have eksctl's default hop limit.
+func (d *deployer) applyIMDSHopLimit() error {
+	if d.IMDSHopLimit == 0 {
+		return nil
+	}
+	instanceIDs, err := d.getNodegroupInstanceIDs()
+	if err != nil {
+		return err
+	}
+	klog.Infof("setting IMDS hop limit to %d on instances: %v", d.IMDSHopLimit, instanceIDs)
+	for _, instanceID := range instanceIDs {
+		_, err := d.ec2Client.ModifyInstanceMetadataOptions(context.TODO(), &ec2.ModifyInstanceMetadataOptionsInput{
+			InstanceId:              aws.String(instanceID),
+			HttpPutResponseHopLimit: aws.Int32(int32(d.IMDSHopLimit)),
+		}, d.ec2Region)
+		if err != nil {
+			return fmt.Errorf("failed to set IMDS hop limit on instance %s: %v", instanceID, err)
+		}
+	}
+	return nil
+}
+
+// getNodegroupInstanceIDs returns the running instances of the nodegroups created by the deployer
+func (d *deployer) getNodegroupInstanceIDs() ([]string, error) {
+	// managed nodegroup instances are tagged by EKS, unmanaged nodegroup instances are tagged by eksctl
+	nodegroupTag := "eks:nodegroup-name"
+	if d.UseUnmanagedNodegroup {
+		nodegroupTag = "alpha.eksctl.io/nodegroup-name"
+	}
+	var instanceIDs []string
+	paginator := ec2.NewDescribeInstancesPaginator(d.ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("tag:" + nodegroupTag),
+				Values: d.nodegroupNames(),
+			},
+			{
+				Name:   aws.String(fmt.Sprintf("tag:kubernetes.io/cluster/%s", d.clusterName)),
+				Values: []string{"owned"},
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{string(ec2types.InstanceStateNamePending), string(ec2types.InstanceStateNameRunning)},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO(), d.ec2Region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe nodegroup instances: %v", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceIDs = append(instanceIDs, aws.ToString(instance.InstanceId))
+			}
+		}
+	}
+	if len(instanceIDs) == 0 {
+		return nil, fmt.Errorf("no instances found for nodegroups %v in cluster %s", d.nodegroupNames(), d.clusterName)
+	}
+	return instanceIDs, nil
+}
@@ -0,0 +1,72 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog"
+)
+
+// kubernetesClient creates a client from the kubeconfig written by Up
+func (d *deployer) kubernetesClient() (*kubernetes.Clientset, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", d.KubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
+	}
+	return clientset, nil
+}
+
+func countReadyNodes(ctx context.Context, clientset kubernetes.Interface) (int, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	ready := 0
+	for _, node := range nodes.Items {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready++
+				break
+			}
+		}
+	}
+	return ready, nil
+}
+
+// waitForAllocatable waits for every node to report a nonzero allocatable amount of the extended resource, e.g. from a device plugin
+func (d *deployer) waitForAllocatable(ctx context.Context, resourceName corev1.ResourceName, timeout time.Duration) error {
+	clientset, err := d.kubernetesClient()
+	if err != nil {
+		return err
+	}
+	klog.Infof("Waiting up to %v for nodes to report allocatable %s", timeout, resourceName)
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.Warningf("failed to list nodes: %v", err)
+			return false, nil
+		}
+		allocatableNodes := 0
+		for _, node := range nodes.Items {
+			if quantity, ok := node.Status.Allocatable[resourceName]; ok && !quantity.IsZero() {
+				allocatableNodes++
+			}
+		}
+		klog.Infof("%d/%d nodes have allocatable %s", allocatableNodes, len(nodes.Items), resourceName)
+		return len(nodes.Items) > 0 && allocatableNodes == len(nodes.Items), nil
+	})
+	if err != nil {
+		return fmt.Errorf("nodes did not report allocatable %s: %v", resourceName, err)
+	}
+	return nil
+}
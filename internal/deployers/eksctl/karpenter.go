@@ -0,0 +1,158 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-k8s-tester/internal/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// defaultKarpenterVersion is the Karpenter chart eksctl installs without --karpenter-version
+	defaultKarpenterVersion = "1.2.1"
+	// karpenterDiscoveryTag is the tag the default EC2NodeClass selects the cluster's subnets and security groups by
+	karpenterDiscoveryTag = "karpenter.sh/discovery"
+	// karpenterNodeClassName is the name of the default NodePool and EC2NodeClass
+	karpenterNodeClassName = "default"
+	karpenterReadyTimeout  = "5m"
+)
+
+// minKarpenterVersion is the first Karpenter version with the v1 NodePool and EC2NodeClass APIs that the defaults are rendered with
+var minKarpenterVersion = version.MustParseGeneric("1.0.0")
+
+func (d *deployer) verifyKarpenter() error {
+	if !d.Karpenter {
+		if d.KarpenterVersion != "" {
+			return fmt.Errorf("--karpenter-version requires --karpenter")
+		}
+		return nil
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--karpenter requires --deploy-target=cluster, eksctl only installs Karpenter with the cluster")
+	}
+	if !d.WithOIDC {
+		return fmt.Errorf("--karpenter requires --with-oidc, for the Karpenter controller's IAM role")
+	}
+	if d.KarpenterVersion == "" {
+		d.KarpenterVersion = defaultKarpenterVersion
+	}
+	karpenterVersion, err := version.ParseGeneric(d.KarpenterVersion)
+	if err != nil {
+		return fmt.Errorf("--karpenter-version is invalid: %v", err)
+	}
+	if karpenterVersion.LessThan(minKarpenterVersion) {
+		return fmt.Errorf("--karpenter-version must be %s or later: %s", minKarpenterVersion, d.KarpenterVersion)
+	}
+	return nil
+}
+
+// setKarpenter renders the karpenter block, and tags the cluster's stacks for discovery.
+// CloudFormation propagates the tag to the VPC's subnets and the shared node security group, which the default EC2NodeClass selects.
+func (d *deployer) setKarpenter(cfg *eksctl_api.ClusterConfig) {
+	if !d.Karpenter {
+		return
+	}
+	cfg.Karpenter = &eksctl_api.Karpenter{
+		Version:              d.KarpenterVersion,
+		CreateServiceAccount: aws.Bool(true),
+	}
+	if cfg.Metadata.Tags == nil {
+		cfg.Metadata.Tags = make(map[string]string)
+	}
+	cfg.Metadata.Tags[karpenterDiscoveryTag] = d.clusterName
+}
+
+// karpenterManifest renders the default EC2NodeClass, which launches nodes with eksctl's Karpenter instance profile,
+// and the default NodePool, which launches on-demand nodes of the nodegroup's architecture
+func (d *deployer) karpenterManifest() ([]byte, error) {
+	amiAlias := "al2023@latest"
+	if d.AMIFamily == eksctl_api.NodeImageFamilyBottlerocket {
+		amiAlias = "bottlerocket@latest"
+	}
+	discoverySelector := []interface{}{
+		map[string]interface{}{"tags": map[string]interface{}{karpenterDiscoveryTag: d.clusterName}},
+	}
+	nodeClass := map[string]interface{}{
+		"apiVersion": "karpenter.k8s.aws/v1",
+		"kind":       "EC2NodeClass",
+		"metadata":   map[string]interface{}{"name": karpenterNodeClassName},
+		"spec": map[string]interface{}{
+			"instanceProfile":            fmt.Sprintf("eksctl-KarpenterNodeInstanceProfile-%s", d.clusterName),
+			"amiSelectorTerms":           []interface{}{map[string]interface{}{"alias": amiAlias}},
+			"subnetSelectorTerms":        discoverySelector,
+			"securityGroupSelectorTerms": discoverySelector,
+		},
+	}
+	architecture := "amd64"
+	if d.nodeArchitecture == architectureARM64 {
+		architecture = "arm64"
+	}
+	nodePool := map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodePool",
+		"metadata":   map[string]interface{}{"name": karpenterNodeClassName},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"nodeClassRef": map[string]interface{}{
+						"group": "karpenter.k8s.aws",
+						"kind":  "EC2NodeClass",
+						"name":  karpenterNodeClassName,
+					},
+					"requirements": []interface{}{
+						map[string]interface{}{"key": "kubernetes.io/os", "operator": "In", "values": []string{"linux"}},
+						map[string]interface{}{"key": "kubernetes.io/arch", "operator": "In", "values": []string{architecture}},
+						map[string]interface{}{"key": "karpenter.sh/capacity-type", "operator": "In", "values": []string{"on-demand"}},
+					},
+				},
+			},
+			"disruption": map[string]interface{}{
+				"consolidationPolicy": "WhenEmptyOrUnderutilized",
+				"consolidateAfter":    "1m",
+			},
+		},
+	}
+	var manifest []byte
+	for _, object := range []map[string]interface{}{nodeClass, nodePool} {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal Karpenter %s: %v", object["kind"], err)
+		}
+		manifest = append(manifest, "---\n"...)
+		manifest = append(manifest, data...)
+	}
+	return manifest, nil
+}
+
+// applyKarpenterNodePool applies the default EC2NodeClass and NodePool, and waits for the EC2NodeClass to resolve its
+// subnets, security groups, and AMIs, so tests can scale workloads onto Karpenter nodes as soon as Up returns
+func (d *deployer) applyKarpenterNodePool(ctx context.Context) error {
+	manifest, err := d.karpenterManifest()
+	if err != nil {
+		return err
+	}
+	manifestFile, err := os.CreateTemp("", "kubetest2-eksctl-karpenter")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(manifestFile.Name())
+	defer manifestFile.Close()
+	if _, err := manifestFile.Write(manifest); err != nil {
+		return err
+	}
+	klog.Infof("Applying default Karpenter NodePool and EC2NodeClass: %s", string(manifest))
+	if err := util.ExecuteCommandContext(ctx, "kubectl", "--kubeconfig", d.KubeconfigPath, "apply", "-f", manifestFile.Name()); err != nil {
+		return fmt.Errorf("failed to apply Karpenter NodePool: %v", err)
+	}
+	klog.Infof("Waiting up to %s for EC2NodeClass %s to become Ready", karpenterReadyTimeout, karpenterNodeClassName)
+	if err := util.ExecuteCommandContext(ctx, "kubectl", "--kubeconfig", d.KubeconfigPath, "wait", "--for=condition=Ready", "ec2nodeclass/"+karpenterNodeClassName, "--timeout="+karpenterReadyTimeout); err != nil {
+		return fmt.Errorf("EC2NodeClass %s did not become Ready: %v", karpenterNodeClassName, err)
+	}
+	return nil
+}
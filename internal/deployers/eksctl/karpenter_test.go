@@ -0,0 +1,50 @@
+package eksctl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyKarpenter(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", KarpenterVersion: "1.1.0"}}
+	assert.ErrorContains(t, d.verifyKarpenter(), "--karpenter-version requires --karpenter")
+
+	d.Karpenter = true
+	assert.ErrorContains(t, d.verifyKarpenter(), "--karpenter requires --with-oidc")
+
+	d.WithOIDC = true
+	d.KarpenterVersion = "0.37.0"
+	assert.ErrorContains(t, d.verifyKarpenter(), "--karpenter-version must be 1.0.0 or later")
+
+	d.KarpenterVersion = ""
+	assert.NoError(t, d.verifyKarpenter())
+	assert.Equal(t, defaultKarpenterVersion, d.KarpenterVersion)
+
+	d.DeployTarget = "nodegroup"
+	assert.ErrorContains(t, d.verifyKarpenter(), "--karpenter requires --deploy-target=cluster")
+}
+
+func Test_CreateClusterConfig_karpenter(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", WithOIDC: true, Karpenter: true, KarpenterVersion: "1.1.0", Tags: []string{"team=e2e"}}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", cfg.Karpenter.Version)
+	assert.True(t, *cfg.Karpenter.CreateServiceAccount)
+	assert.Equal(t, map[string]string{"team": "e2e", "karpenter.sh/discovery": "test"}, cfg.Metadata.Tags)
+}
+
+func Test_karpenterManifest(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{Karpenter: true}, clusterName: "test", nodeArchitecture: architectureARM64}
+	manifest, err := d.karpenterManifest()
+	assert.NoError(t, err)
+	documents := strings.Split(strings.TrimPrefix(string(manifest), "---\n"), "---\n")
+	assert.Len(t, documents, 2)
+	assert.Contains(t, documents[0], "kind: EC2NodeClass")
+	assert.Contains(t, documents[0], "instanceProfile: eksctl-KarpenterNodeInstanceProfile-test")
+	assert.Contains(t, documents[0], "karpenter.sh/discovery: test")
+	assert.Contains(t, documents[0], "alias: al2023@latest")
+	assert.Contains(t, documents[1], "kind: NodePool")
+	assert.Contains(t, documents[1], "- arm64")
+}
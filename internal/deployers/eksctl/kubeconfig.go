@@ -0,0 +1,54 @@
+package eksctl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/klog"
+)
+
+// verifyAdditionalKubeconfigs checks that each --additional-kubeconfig can be written,
+// creating its directory if needed, so an unwritable path fails before the cluster is created
+func (d *deployer) verifyAdditionalKubeconfigs() error {
+	for _, path := range d.AdditionalKubeconfigs {
+		if path == "" {
+			return fmt.Errorf("--additional-kubeconfig must not be empty")
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return fmt.Errorf("--additional-kubeconfig %s is a directory", path)
+		}
+		dir := filepath.Dir(path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory for --additional-kubeconfig %s: %v", path, err)
+		}
+		f, err := os.CreateTemp(dir, ".kubetest2-eksctl-write-check")
+		if err != nil {
+			return fmt.Errorf("--additional-kubeconfig %s is not writable: %v", path, err)
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+	return nil
+}
+
+// copyKubeconfig copies the kubeconfig to each --additional-kubeconfig
+func (d *deployer) copyKubeconfig(kubeconfigPath string) error {
+	if len(d.AdditionalKubeconfigs) == 0 {
+		return nil
+	}
+	kubeconfig, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to read kubeconfig %s: %v", kubeconfigPath, err)
+	}
+	for _, path := range d.AdditionalKubeconfigs {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for --additional-kubeconfig %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, kubeconfig, 0600); err != nil {
+			return fmt.Errorf("failed to write kubeconfig to %s: %v", path, err)
+		}
+		klog.Infof("Copied kubeconfig to %s", path)
+	}
+	return nil
+}
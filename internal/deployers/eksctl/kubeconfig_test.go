@@ -0,0 +1,28 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_copyKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfigPath := filepath.Join(dir, "kubeconfig")
+	assert.NoError(t, os.WriteFile(kubeconfigPath, []byte("apiVersion: v1\nkind: Config\n"), 0600))
+	additional := []string{filepath.Join(dir, "shared", "kubeconfig"), filepath.Join(dir, "other", "nested", "config")}
+
+	d := &deployer{UpOptions: &UpOptions{AdditionalKubeconfigs: additional}}
+	assert.NoError(t, d.verifyAdditionalKubeconfigs())
+	assert.NoError(t, d.copyKubeconfig(kubeconfigPath))
+	for _, path := range additional {
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "apiVersion: v1\nkind: Config\n", string(content))
+	}
+
+	d.AdditionalKubeconfigs = []string{dir}
+	assert.ErrorContains(t, d.verifyAdditionalKubeconfigs(), "is a directory")
+}
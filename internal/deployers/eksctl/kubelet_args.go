@@ -0,0 +1,53 @@
+package eksctl
+
+import (
+	"fmt"
+	"strings"
+
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+func (d *deployer) verifyKubeletExtraArgs() error {
+	if d.KubeletExtraArgs == "" {
+		return nil
+	}
+	if d.AMI == "" {
+		return fmt.Errorf("--kubelet-extra-args requires --ami, the bootstrap of EKS-optimized AMIs is not overridden")
+	}
+	if d.AMIFamily == eksctl_api.NodeImageFamilyBottlerocket {
+		return fmt.Errorf("--kubelet-extra-args is not supported with Bottlerocket, kubelet is configured through Bottlerocket settings")
+	}
+	for _, arg := range strings.Fields(d.KubeletExtraArgs) {
+		if !strings.HasPrefix(arg, "--") {
+			return fmt.Errorf("--kubelet-extra-args must be a list of --flag=value arguments: %q", arg)
+		}
+	}
+	return nil
+}
+
+// overrideBootstrapCommand returns the bootstrap override for a custom AMI, or nil if eksctl's default should be used.
+// For AL2 this is the bootstrap.sh invocation; for AL2023 it's a nodeadm NodeConfig that is merged with the one eksctl generates.
+// Windows AMIs always run their own bootstrap script, which eksctl doesn't allow overriding.
+func (d *deployer) overrideBootstrapCommand(amiFamily string) (*string, error) {
+	if d.AMI == "" || eksctl_api.IsWindowsImage(amiFamily) {
+		return nil, nil
+	}
+	switch amiFamily {
+	case eksctl_api.NodeImageFamilyAmazonLinux2:
+		kubeletExtraArgs := "--node-labels=${NODE_LABELS}"
+		if d.KubeletExtraArgs != "" {
+			kubeletExtraArgs += " " + d.KubeletExtraArgs
+		}
+		bootstrapCommand := fmt.Sprintf(`#!/bin/bash
+source /var/lib/cloud/scripts/eksctl/bootstrap.helper.sh
+/etc/eks/bootstrap.sh %s --kubelet-extra-args "%s"`, d.clusterName, kubeletExtraArgs)
+		return &bootstrapCommand, nil
+	case eksctl_api.NodeImageFamilyAmazonLinux2023:
+		nodeConfig, err := d.nodeadmConfig()
+		if err != nil {
+			return nil, err
+		}
+		return &nodeConfig, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,36 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_overrideBootstrapCommand(t *testing.T) {
+	d := &deployer{
+		UpOptions:   &UpOptions{AMI: "ami-123", KubeletExtraArgs: "--eviction-hard=memory.available<5% --max-pods=20"},
+		clusterName: "test",
+	}
+	bootstrapCommand, err := d.overrideBootstrapCommand("AmazonLinux2")
+	assert.NoError(t, err)
+	assert.Contains(t, *bootstrapCommand, `/etc/eks/bootstrap.sh test --kubelet-extra-args "--node-labels=${NODE_LABELS} --eviction-hard=memory.available<5% --max-pods=20"`)
+
+	bootstrapCommand, err = d.overrideBootstrapCommand("AmazonLinux2023")
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: node.eks.aws/v1alpha1
+kind: NodeConfig
+spec:
+  cluster:
+    name: test
+  kubelet:
+    flags:
+    - --eviction-hard=memory.available<5%
+    - --max-pods=20
+`, *bootstrapCommand)
+
+	d.AMI = ""
+	assert.ErrorContains(t, d.verifyKubeletExtraArgs(), "requires --ami")
+	bootstrapCommand, err = d.overrideBootstrapCommand("AmazonLinux2")
+	assert.NoError(t, err)
+	assert.Nil(t, bootstrapCommand)
+}
@@ -0,0 +1,70 @@
+package eksctl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/kubetest2/pkg/artifacts"
+	"sigs.k8s.io/kubetest2/pkg/metadata"
+)
+
+// writeClusterMetadata logs the cluster's ARN, API endpoint, and OIDC issuer, and adds them and its IP family to kubetest2's metadata.json,
+// so later steps don't need to describe the cluster themselves. It fails if --ipv6 didn't produce an IPv6 cluster.
+func (d *deployer) writeClusterMetadata() error {
+	out, err := d.eksClient.DescribeCluster(context.TODO(), &eks.DescribeClusterInput{
+		Name: aws.String(d.clusterName),
+	}, d.eksRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe cluster %s: %v", d.clusterName, err)
+	}
+	values := map[string]string{
+		"cluster-arn":      aws.ToString(out.Cluster.Arn),
+		"cluster-endpoint": aws.ToString(out.Cluster.Endpoint),
+	}
+	if networkConfig := out.Cluster.KubernetesNetworkConfig; networkConfig != nil {
+		values["cluster-ip-family"] = string(networkConfig.IpFamily)
+	}
+	if d.IPv6 && values["cluster-ip-family"] != string(ekstypes.IpFamilyIpv6) {
+		return fmt.Errorf("cluster %s was created with IP family %q, expected %s", d.clusterName, values["cluster-ip-family"], ekstypes.IpFamilyIpv6)
+	}
+	if out.Cluster.Identity != nil && out.Cluster.Identity.Oidc != nil {
+		values["cluster-oidc-issuer"] = aws.ToString(out.Cluster.Identity.Oidc.Issuer)
+	}
+	klog.Infof("Cluster ARN: %s, endpoint: %s, OIDC issuer: %s", values["cluster-arn"], values["cluster-endpoint"], values["cluster-oidc-issuer"])
+	return addToMetadata(filepath.Join(artifacts.BaseDir(), "metadata.json"), values)
+}
+
+// addToMetadata adds the values to the metadata file, creating it if it doesn't exist
+func addToMetadata(metadataPath string, values map[string]string) error {
+	var meta *metadata.CustomJSON
+	if existing, err := os.ReadFile(metadataPath); err == nil {
+		meta, err = metadata.NewCustomJSON(bytes.NewReader(existing))
+		if err != nil {
+			return fmt.Errorf("failed to read metadata from %s: %v", metadataPath, err)
+		}
+	} else if os.IsNotExist(err) {
+		meta, _ = metadata.NewCustomJSON(nil)
+	} else {
+		return fmt.Errorf("failed to read metadata from %s: %v", metadataPath, err)
+	}
+	for key, value := range values {
+		if err := meta.Add(key, value); err != nil {
+			return err
+		}
+	}
+	var buf bytes.Buffer
+	if err := meta.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(metadataPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write metadata to %s: %v", metadataPath, err)
+	}
+	return nil
+}
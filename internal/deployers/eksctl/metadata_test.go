@@ -0,0 +1,21 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_addToMetadata(t *testing.T) {
+	metadataPath := filepath.Join(t.TempDir(), "metadata.json")
+
+	assert.NoError(t, addToMetadata(metadataPath, map[string]string{"deployer-version": "v1"}))
+	assert.NoError(t, addToMetadata(metadataPath, map[string]string{"cluster-arn": "arn:aws:eks:us-west-2:123456789012:cluster/test"}))
+	content, err := os.ReadFile(metadataPath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"deployer-version":"v1","cluster-arn":"arn:aws:eks:us-west-2:123456789012:cluster/test"}`, string(content))
+
+	assert.Error(t, addToMetadata(metadataPath, map[string]string{"deployer-version": "v2"}))
+}
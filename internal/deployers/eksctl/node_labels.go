@@ -0,0 +1,235 @@
+package eksctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog"
+	"sigs.k8s.io/kubetest2/pkg/artifacts"
+)
+
+// taintEffects are the effects a node taint may have
+var taintEffects = []corev1.TaintEffect{
+	corev1.TaintEffectNoSchedule,
+	corev1.TaintEffectPreferNoSchedule,
+	corev1.TaintEffectNoExecute,
+}
+
+// parseNodeLabels parses key=value pairs from the flag into the nodegroup's labels
+func parseNodeLabels(flag string, pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s expected key=value pair: %s", flag, pair)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("%s key %q is invalid: %s", flag, key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("%s value for %s is invalid: %s", flag, key, strings.Join(errs, "; "))
+		}
+		if isRestrictedNodeLabel(key) {
+			return nil, fmt.Errorf("%s key %s is in a kubernetes.io or k8s.io namespace that kubelet is not allowed to set, use node.kubernetes.io/ or a custom prefix", flag, key)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// isRestrictedNodeLabel reports whether the NodeRestriction admission plugin prevents kubelet from registering the node with the label
+func isRestrictedNodeLabel(key string) bool {
+	prefix, _, ok := strings.Cut(key, "/")
+	if !ok {
+		return false
+	}
+	if prefix == "node.kubernetes.io" || strings.HasSuffix(prefix, ".node.kubernetes.io") ||
+		prefix == "kubelet.kubernetes.io" || strings.HasSuffix(prefix, ".kubelet.kubernetes.io") {
+		return false
+	}
+	for _, domain := range []string{"kubernetes.io", "k8s.io"} {
+		if prefix == domain || strings.HasSuffix(prefix, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNodeTaints parses --node-taints, each in kubectl's key[=value]:effect format, into the nodegroup's taints
+func parseNodeTaints(specs []string) ([]eksctl_api.NodeGroupTaint, error) {
+	var taints []eksctl_api.NodeGroupTaint
+	for _, spec := range specs {
+		keyValue, effect, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("--node-taints expected key[=value]:effect: %s", spec)
+		}
+		key, value, _ := strings.Cut(keyValue, "=")
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("--node-taints key %q is invalid: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("--node-taints value for %s is invalid: %s", key, strings.Join(errs, "; "))
+		}
+		if !slices.Contains(taintEffects, corev1.TaintEffect(effect)) {
+			return nil, fmt.Errorf("--node-taints effect for %s must be one of %v: %s", key, taintEffects, effect)
+		}
+		taints = append(taints, eksctl_api.NodeGroupTaint{
+			Key:    key,
+			Value:  value,
+			Effect: corev1.TaintEffect(effect),
+		})
+	}
+	return taints, nil
+}
+
+func (d *deployer) verifyNodeLabelsAndTaints() error {
+	_, _, err := d.nodeLabelsAndTaints()
+	return err
+}
+
+// nodeLabelsAndTaints returns the nodegroup's labels and taints from --node-labels and --node-taints,
+// along with a label and a NoSchedule taint for each --reserve pair
+func (d *deployer) nodeLabelsAndTaints() (map[string]string, []eksctl_api.NodeGroupTaint, error) {
+	labels, err := parseNodeLabels("--node-labels", d.NodeLabels)
+	if err != nil {
+		return nil, nil, err
+	}
+	taints, err := parseNodeTaints(d.NodeTaints)
+	if err != nil {
+		return nil, nil, err
+	}
+	reservations, err := parseNodeLabels("--reserve", d.Reserve)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, key := range slices.Sorted(maps.Keys(reservations)) {
+		value := reservations[key]
+		if labelValue, ok := labels[key]; ok && labelValue != value {
+			return nil, nil, fmt.Errorf("--reserve %s=%s conflicts with --node-labels %s=%s", key, value, key, labelValue)
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+		taint := eksctl_api.NodeGroupTaint{Key: key, Value: value, Effect: corev1.TaintEffectNoSchedule}
+		if !slices.Contains(taints, taint) {
+			taints = append(taints, taint)
+		}
+	}
+	return labels, taints, nil
+}
+
+// reservedNodeTolerations returns the tolerations that pods need to be scheduled on nodes reserved with --reserve
+func (d *deployer) reservedNodeTolerations() ([]corev1.Toleration, error) {
+	reservations, err := parseNodeLabels("--reserve", d.Reserve)
+	if err != nil {
+		return nil, err
+	}
+	var tolerations []corev1.Toleration
+	for _, key := range slices.Sorted(maps.Keys(reservations)) {
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      key,
+			Operator: corev1.TolerationOpEqual,
+			Value:    reservations[key],
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	return tolerations, nil
+}
+
+// checkNodeLabelsAndTaints reads the nodegroups' Node objects and fails if any are missing the labels or taints from --node-labels, --node-taints, or --reserve,
+// e.g. because a bootstrap override dropped them. The verified state is recorded in kubetest2's metadata.json.
+func (d *deployer) checkNodeLabelsAndTaints() error {
+	if len(d.NodeLabels) == 0 && len(d.NodeTaints) == 0 && len(d.Reserve) == 0 {
+		return nil
+	}
+	labels, taints, err := d.nodeLabelsAndTaints()
+	if err != nil {
+		return err
+	}
+	instanceIDs, err := d.getNodegroupInstanceIDs()
+	if err != nil {
+		return err
+	}
+	clientset, err := d.kubernetesClient()
+	if err != nil {
+		return err
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %v", err)
+	}
+	checked := 0
+	var mismatches []string
+	for _, node := range nodes.Items {
+		if !slices.Contains(instanceIDs, providerInstanceID(node.Spec.ProviderID)) {
+			continue
+		}
+		checked++
+		for _, mismatch := range nodeLabelAndTaintMismatches(node, labels, taints) {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %s", node.Name, mismatch))
+		}
+	}
+	if checked == 0 {
+		return fmt.Errorf("no nodes found for nodegroups %v, unable to verify the configured labels and taints", d.nodegroupNames())
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("nodes are missing the configured labels or taints:\n%s", strings.Join(mismatches, "\n"))
+	}
+	klog.Infof("Verified the configured labels and taints on %d nodes", checked)
+	values := map[string]string{
+		"node-labels-taints-verified": "true",
+	}
+	if len(d.Reserve) > 0 {
+		tolerations, err := d.reservedNodeTolerations()
+		if err != nil {
+			return err
+		}
+		hint, err := json.Marshal(tolerations)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tolerations for --reserve: %v", err)
+		}
+		klog.Infof("Pods must tolerate the reserved nodes' taints to be scheduled on them: %s", hint)
+		values["reserved-node-tolerations"] = string(hint)
+	}
+	return addToMetadata(filepath.Join(artifacts.BaseDir(), "metadata.json"), values)
+}
+
+// providerInstanceID returns the EC2 instance ID from a node's provider ID, e.g. aws:///us-west-2a/i-0123456789abcdef0
+func providerInstanceID(providerID string) string {
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
+
+// nodeLabelAndTaintMismatches describes each of the expected labels and taints that the node doesn't have
+func nodeLabelAndTaintMismatches(node corev1.Node, labels map[string]string, taints []eksctl_api.NodeGroupTaint) []string {
+	var mismatches []string
+	for key, value := range labels {
+		actual, ok := node.Labels[key]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("label %s=%s is missing", key, value))
+		} else if actual != value {
+			mismatches = append(mismatches, fmt.Sprintf("label %s is %q, expected %q", key, actual, value))
+		}
+	}
+	for _, taint := range taints {
+		if !slices.ContainsFunc(node.Spec.Taints, func(t corev1.Taint) bool {
+			return t.Key == taint.Key && t.Value == taint.Value && t.Effect == taint.Effect
+		}) {
+			mismatches = append(mismatches, fmt.Sprintf("taint %s=%s:%s is missing", taint.Key, taint.Value, taint.Effect))
+		}
+	}
+	slices.Sort(mismatches)
+	return mismatches
+}
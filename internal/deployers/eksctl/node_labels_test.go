@@ -0,0 +1,102 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_parseNodeLabels(t *testing.T) {
+	labels, err := parseNodeLabels("--node-labels", []string{"team=a", "example.com/role=worker", "node.kubernetes.io/pool=test"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "a", "example.com/role": "worker", "node.kubernetes.io/pool": "test"}, labels)
+
+	_, err = parseNodeLabels("--node-labels", []string{"team"})
+	assert.ErrorContains(t, err, "expected key=value pair")
+	_, err = parseNodeLabels("--node-labels", []string{"team=a b"})
+	assert.ErrorContains(t, err, "value for team is invalid")
+	_, err = parseNodeLabels("--node-labels", []string{"node-role.kubernetes.io/worker="})
+	assert.ErrorContains(t, err, "kubelet is not allowed to set")
+}
+
+func Test_parseNodeTaints(t *testing.T) {
+	taints, err := parseNodeTaints([]string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule"})
+	assert.NoError(t, err)
+	assert.Equal(t, []eksctl_api.NodeGroupTaint{
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+	}, taints)
+
+	_, err = parseNodeTaints([]string{"dedicated=gpu"})
+	assert.ErrorContains(t, err, "expected key[=value]:effect")
+	_, err = parseNodeTaints([]string{"dedicated=gpu:NoRun"})
+	assert.ErrorContains(t, err, "effect for dedicated must be one of")
+}
+
+func Test_nodeLabelAndTaintMismatches(t *testing.T) {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a", "role": "db"}},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		}},
+	}
+	assert.Empty(t, nodeLabelAndTaintMismatches(node,
+		map[string]string{"team": "a"},
+		[]eksctl_api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}))
+	assert.Equal(t, []string{
+		"label role is \"db\", expected \"web\"",
+		"label zone=a is missing",
+		"taint dedicated=gpu:NoExecute is missing",
+	}, nodeLabelAndTaintMismatches(node,
+		map[string]string{"role": "web", "zone": "a"},
+		[]eksctl_api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoExecute}}))
+}
+
+func Test_providerInstanceID(t *testing.T) {
+	assert.Equal(t, "i-0123456789abcdef0", providerInstanceID("aws:///us-west-2a/i-0123456789abcdef0"))
+}
+
+func Test_CreateClusterConfig_nodeLabelsAndTaints(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{
+		ClusterName: "test",
+		NodeLabels:  []string{"team=a"},
+		NodeTaints:  []string{"dedicated=gpu:NoSchedule"},
+	}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "a"}, cfg.ManagedNodeGroups[0].Labels)
+	assert.Equal(t, []eksctl_api.NodeGroupTaint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}, cfg.ManagedNodeGroups[0].Taints)
+}
+
+func Test_nodeLabelsAndTaints_reserve(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{
+		NodeLabels: []string{"team=a"},
+		NodeTaints: []string{"dedicated=gpu:NoSchedule"},
+		Reserve:    []string{"dedicated=gpu", "example.com/owner=perf"},
+	}}
+	labels, taints, err := d.nodeLabelsAndTaints()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "a", "dedicated": "gpu", "example.com/owner": "perf"}, labels)
+	assert.Equal(t, []eksctl_api.NodeGroupTaint{
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "example.com/owner", Value: "perf", Effect: corev1.TaintEffectNoSchedule},
+	}, taints)
+
+	tolerations, err := d.reservedNodeTolerations()
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "example.com/owner", Operator: corev1.TolerationOpEqual, Value: "perf", Effect: corev1.TaintEffectNoSchedule},
+	}, tolerations)
+
+	d.NodeLabels = []string{"dedicated=cpu"}
+	_, _, err = d.nodeLabelsAndTaints()
+	assert.ErrorContains(t, err, "--reserve dedicated=gpu conflicts with --node-labels dedicated=cpu")
+
+	d = &deployer{UpOptions: &UpOptions{Reserve: []string{"dedicated"}}}
+	_, _, err = d.nodeLabelsAndTaints()
+	assert.ErrorContains(t, err, "--reserve expected key=value pair")
+}
@@ -0,0 +1,40 @@
+package eksctl
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// nodeNamePrefixPattern keeps the Name tag friendly to inventory tooling that splits on the prefix
+var nodeNamePrefixPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,62}$`)
+
+func (d *deployer) verifyNodeNamePrefix() error {
+	if d.NodeNamePrefix == "" {
+		return nil
+	}
+	if !nodeNamePrefixPattern.MatchString(d.NodeNamePrefix) {
+		return fmt.Errorf("--node-name-prefix must be at most 63 letters, digits, '.', '_', or '-', starting with a letter or digit: %q", d.NodeNamePrefix)
+	}
+	return nil
+}
+
+// nodeTags returns the tags for a nodegroup's instances, or nil to keep eksctl's defaults.
+// The --nodegroup-tags-file takes precedence over the run's --tags, and the Name tag from --node-name-prefix over both.
+func (d *deployer) nodeTags(nodegroupName string) map[string]string {
+	// --tags were checked by verifyUpFlags, and CreateClusterConfig fails on them before the nodegroups are rendered
+	runTags, _ := parseStackTags(d.Tags)
+	if d.NodeNamePrefix == "" && len(d.nodegroupTags) == 0 && len(runTags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string)
+	for key, value := range runTags {
+		tags[key] = value
+	}
+	for key, value := range d.nodegroupTags {
+		tags[key] = value
+	}
+	if d.NodeNamePrefix != "" {
+		tags["Name"] = fmt.Sprintf("%s-%s", d.NodeNamePrefix, nodegroupName)
+	}
+	return tags
+}
@@ -0,0 +1,77 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+)
+
+// nodeadmCluster is the cluster section of a nodeadm NodeConfig
+type nodeadmCluster struct {
+	Name                 string `json:"name"`
+	APIServerEndpoint    string `json:"apiServerEndpoint,omitempty"`
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+	CIDR                 string `json:"cidr,omitempty"`
+}
+
+// nodeadmConfig renders the nodeadm NodeConfig for a custom AL2023 AMI, with the cluster's details and the --kubelet-extra-args.
+// The API endpoint, certificate authority, and service CIDR are only known before eksctl runs when adding a nodegroup to an
+// existing cluster; for a new cluster, nodeadm takes them from the NodeConfig eksctl generates once the cluster is created.
+func (d *deployer) nodeadmConfig() (string, error) {
+	cluster := nodeadmCluster{Name: d.clusterName}
+	if d.existingCluster != nil {
+		cluster = *d.existingCluster
+	}
+	spec := map[string]interface{}{
+		"cluster": cluster,
+	}
+	if d.KubeletExtraArgs != "" {
+		spec["kubelet"] = map[string]interface{}{
+			"flags": strings.Fields(d.KubeletExtraArgs),
+		}
+	}
+	nodeConfig, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "node.eks.aws/v1alpha1",
+		"kind":       "NodeConfig",
+		"spec":       spec,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render nodeadm config: %v", err)
+	}
+	return string(nodeConfig), nil
+}
+
+// describeExistingCluster looks up the details of the cluster a custom AL2023 AMI's nodegroup is added to, for its nodeadm config
+func (d *deployer) describeExistingCluster() error {
+	if d.DeployTarget != "nodegroup" || d.AMI == "" || d.AMIFamily != eksctl_api.NodeImageFamilyAmazonLinux2023 {
+		return nil
+	}
+	out, err := d.eksClient.DescribeCluster(context.TODO(), &eks.DescribeClusterInput{
+		Name: aws.String(d.clusterName),
+	}, d.eksRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe cluster %s for the nodeadm config: %v", d.clusterName, err)
+	}
+	cluster := nodeadmCluster{
+		Name:              d.clusterName,
+		APIServerEndpoint: aws.ToString(out.Cluster.Endpoint),
+	}
+	if out.Cluster.CertificateAuthority != nil {
+		cluster.CertificateAuthority = aws.ToString(out.Cluster.CertificateAuthority.Data)
+	}
+	if networkConfig := out.Cluster.KubernetesNetworkConfig; networkConfig != nil {
+		cluster.CIDR = aws.ToString(networkConfig.ServiceIpv4Cidr)
+		if networkConfig.ServiceIpv6Cidr != nil {
+			cluster.CIDR = aws.ToString(networkConfig.ServiceIpv6Cidr)
+		}
+	}
+	klog.Infof("Using endpoint %s and service CIDR %s of cluster %s for the nodeadm config", cluster.APIServerEndpoint, cluster.CIDR, d.clusterName)
+	d.existingCluster = &cluster
+	return nil
+}
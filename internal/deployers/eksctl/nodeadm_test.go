@@ -0,0 +1,57 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nodeadmConfig(t *testing.T) {
+	d := &deployer{
+		UpOptions:   &UpOptions{AMI: "ami-123"},
+		clusterName: "test",
+	}
+	nodeConfig, err := d.nodeadmConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: node.eks.aws/v1alpha1
+kind: NodeConfig
+spec:
+  cluster:
+    name: test
+`, nodeConfig)
+
+	d.KubeletExtraArgs = "--max-pods=20"
+	d.existingCluster = &nodeadmCluster{
+		Name:                 "test",
+		APIServerEndpoint:    "https://example.eks.amazonaws.com",
+		CertificateAuthority: "Y2VydA==",
+		CIDR:                 "10.100.0.0/16",
+	}
+	nodeConfig, err = d.nodeadmConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: node.eks.aws/v1alpha1
+kind: NodeConfig
+spec:
+  cluster:
+    apiServerEndpoint: https://example.eks.amazonaws.com
+    certificateAuthority: Y2VydA==
+    cidr: 10.100.0.0/16
+    name: test
+  kubelet:
+    flags:
+    - --max-pods=20
+`, nodeConfig)
+}
+
+func Test_CreateClusterConfig_al2023CustomAMI(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{
+		ClusterName: "test",
+		AMI:         "ami-123",
+		AMIFamily:   "AmazonLinux2023",
+	}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	mng := cfg.ManagedNodeGroups[0]
+	assert.Equal(t, "ami-123", mng.AMI)
+	assert.Contains(t, *mng.OverrideBootstrapCommand, "kind: NodeConfig")
+}
@@ -0,0 +1,45 @@
+package eksctl
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+// loadNodegroupTags reads the --nodegroup-tags-file, a YAML map of tag keys to values.
+// Each value is a text/template rendered with the cluster config template params, e.g. `Name: "{{.ClusterName}}-ng"`.
+func (d *deployer) loadNodegroupTags() error {
+	data, err := os.ReadFile(d.NodegroupTagsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read --nodegroup-tags-file: %v", err)
+	}
+	var tagTemplates map[string]string
+	if err := yaml.UnmarshalStrict(data, &tagTemplates); err != nil {
+		return fmt.Errorf("failed to parse --nodegroup-tags-file %s: %v", d.NodegroupTagsFile, err)
+	}
+	params := clusterConfigTemplateParams{
+		UpOptions:   *d.UpOptions,
+		ClusterName: d.clusterName,
+		Region:      d.region(),
+	}
+	tags := make(map[string]string)
+	for key, value := range tagTemplates {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse template of tag %s: %v", key, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, params); err != nil {
+			return fmt.Errorf("failed to render template of tag %s: %v", key, err)
+		}
+		if err := verifyTag("--nodegroup-tags-file", key, buf.String()); err != nil {
+			return err
+		}
+		tags[key] = buf.String()
+	}
+	d.nodegroupTags = tags
+	return nil
+}
@@ -0,0 +1,27 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadNodegroupTags(t *testing.T) {
+	tagsFile := filepath.Join(t.TempDir(), "tags.yaml")
+	assert.NoError(t, os.WriteFile(tagsFile, []byte(`Name: "{{.ClusterName}}-ng"
+region: "{{.Region}}"
+team: node
+`), 0644))
+	d := &deployer{UpOptions: &UpOptions{Region: "us-west-2", NodegroupTagsFile: tagsFile, NodeNamePrefix: "ci"}, clusterName: "test"}
+	assert.NoError(t, d.loadNodegroupTags())
+	assert.Equal(t, map[string]string{"Name": "test-ng", "region": "us-west-2", "team": "node"}, d.nodegroupTags)
+	assert.Equal(t, map[string]string{"Name": "ci-ng-1", "region": "us-west-2", "team": "node"}, d.nodeTags("ng-1"))
+
+	assert.NoError(t, os.WriteFile(tagsFile, []byte(`Name: "{{.ClusterName"`), 0644))
+	assert.ErrorContains(t, d.loadNodegroupTags(), "failed to parse template of tag Name")
+
+	assert.NoError(t, os.WriteFile(tagsFile, []byte(`"aws:owner": "{{.ClusterName}}"`), 0644))
+	assert.ErrorContains(t, d.loadNodegroupTags(), "--nodegroup-tags-file key must not start with the reserved prefix 'aws:': aws:owner")
+}
@@ -0,0 +1,79 @@
+package eksctl
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+)
+
+// nodegroupSpec is an entry of the --nodegroups-file, a YAML list of nodegroups to create instead of the single nodegroup from flags.
+// Options that are left out default to the flags:
+//
+//	# nodegroups.yaml
+//	- name: general
+//	  instanceTypes: [m5.xlarge]
+//	- name: gpu
+//	  amiFamily: Bottlerocket
+//	  instanceTypes: [g5.xlarge]
+//	  nodes: 2
+//	  volumeSize: 200
+type nodegroupSpec struct {
+	Name          string   `json:"name"`
+	AMIFamily     string   `json:"amiFamily"`
+	InstanceTypes []string `json:"instanceTypes"`
+	Nodes         int      `json:"nodes"`
+	VolumeSize    int      `json:"volumeSize"`
+}
+
+// loadNodegroupsFile reads and checks the --nodegroups-file
+func (d *deployer) loadNodegroupsFile() error {
+	if d.NodegroupName != "" {
+		return fmt.Errorf("--nodegroups-file cannot be used with --nodegroup-name, name the nodegroups in the file instead")
+	}
+	if len(d.NodegroupAMIFamilies) > 0 {
+		return fmt.Errorf("--nodegroups-file cannot be used with --nodegroup-ami-families, set amiFamily on the nodegroups in the file instead")
+	}
+	data, err := os.ReadFile(d.NodegroupsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read --nodegroups-file: %v", err)
+	}
+	var specs []nodegroupSpec
+	if err := yaml.UnmarshalStrict(data, &specs); err != nil {
+		return fmt.Errorf("failed to parse --nodegroups-file %s: %v", d.NodegroupsFile, err)
+	}
+	if len(specs) == 0 {
+		return fmt.Errorf("--nodegroups-file %s has no nodegroups", d.NodegroupsFile)
+	}
+	var names []string
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return fmt.Errorf("--nodegroups-file nodegroups must have a name")
+		}
+		if slices.Contains(names, spec.Name) {
+			return fmt.Errorf("--nodegroups-file has more than one nodegroup named %s", spec.Name)
+		}
+		names = append(names, spec.Name)
+		if spec.Nodes < 0 || spec.VolumeSize < 0 {
+			return fmt.Errorf("--nodegroups-file nodegroup %s must not have a negative nodes or volumeSize", spec.Name)
+		}
+		if d.PrefixDelegation && len(spec.InstanceTypes) > 0 {
+			return fmt.Errorf("--nodegroups-file nodegroup %s cannot set its own instanceTypes with --prefix-delegation, which sets max pods for --instance-types", spec.Name)
+		}
+		if d.UseUnmanagedNodegroup && len(spec.InstanceTypes) > 1 && !d.Spot {
+			return fmt.Errorf("--nodegroups-file nodegroup %s has %d instance types, unmanaged nodegroups only support one without --spot", spec.Name, len(spec.InstanceTypes))
+		}
+		if spec.AMIFamily == "" || spec.AMIFamily == d.AMIFamily {
+			continue
+		}
+		if !slices.Contains(nodegroupAMIFamilies, spec.AMIFamily) {
+			return fmt.Errorf("--nodegroups-file nodegroup %s amiFamily must be one of %v: %s", spec.Name, nodegroupAMIFamilies, spec.AMIFamily)
+		}
+		if d.AMI != "" || d.NodeAMIType != "" || d.KubeletExtraArgs != "" || d.NodeReleaseVersion != "" {
+			return fmt.Errorf("--nodegroups-file nodegroup %s cannot set its own amiFamily with --ami, --node-ami-type, --kubelet-extra-args, or --nodegroup-release-version, which apply to a single AMI family", spec.Name)
+		}
+	}
+	d.nodegroupSpecs = specs
+	return nil
+}
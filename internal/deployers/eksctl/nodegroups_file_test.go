@@ -0,0 +1,70 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadNodegroupsFile(t *testing.T) {
+	nodegroupsFile := filepath.Join(t.TempDir(), "nodegroups.yaml")
+	assert.NoError(t, os.WriteFile(nodegroupsFile, []byte(`- name: general
+- name: gpu
+  amiFamily: Bottlerocket
+  instanceTypes: [g5.xlarge]
+  nodes: 2
+  volumeSize: 200
+`), 0644))
+	d := &deployer{UpOptions: &UpOptions{
+		ClusterName:       "test",
+		Nodes:             3,
+		InstanceTypes:     []string{"m5.xlarge"},
+		VolumeSize:        80,
+		AMIFamily:         "AmazonLinux2023",
+		AvailabilityZones: []string{"us-west-2a", "us-west-2b"},
+		NodegroupsFile:    nodegroupsFile,
+	}}
+	assert.NoError(t, d.loadNodegroupsFile())
+	assert.Equal(t, []string{"general", "gpu"}, d.nodegroupNames())
+
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Len(t, cfg.ManagedNodeGroups, 2)
+	general, gpu := cfg.ManagedNodeGroups[0], cfg.ManagedNodeGroups[1]
+	assert.Equal(t, "AmazonLinux2023", general.AMIFamily)
+	assert.Equal(t, []string{"m5.xlarge"}, general.InstanceTypes)
+	assert.Equal(t, 3, *general.MaxSize)
+	assert.Equal(t, 80, *general.VolumeSize)
+	assert.Equal(t, "Bottlerocket", gpu.AMIFamily)
+	assert.Equal(t, []string{"g5.xlarge"}, gpu.InstanceTypes)
+	assert.Equal(t, 2, *gpu.MinSize)
+	assert.Equal(t, 2, *gpu.MaxSize)
+	assert.Equal(t, 2, *gpu.DesiredCapacity)
+	assert.Equal(t, 200, *gpu.VolumeSize)
+
+	d.NodegroupPerAZ = true
+	assert.Equal(t, []string{"general-us-west-2a", "general-us-west-2b", "gpu-us-west-2a", "gpu-us-west-2b"}, d.nodegroupNames())
+
+	d.AMI = "ami-0123456789abcdef0"
+	assert.ErrorContains(t, d.loadNodegroupsFile(), "nodegroup gpu cannot set its own amiFamily with --ami")
+
+	assert.NoError(t, os.WriteFile(nodegroupsFile, []byte(`- name: a
+- name: a
+`), 0644))
+	assert.ErrorContains(t, d.loadNodegroupsFile(), "more than one nodegroup named a")
+
+	d.NodegroupName = "ng"
+	assert.ErrorContains(t, d.loadNodegroupsFile(), "cannot be used with --nodegroup-name")
+}
+
+func Test_requestedInstanceTypes(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{InstanceTypes: []string{"m5.large"}}}
+	d.nodegroupSpecs = []nodegroupSpec{
+		{Name: "gpu", InstanceTypes: []string{"g5.xlarge", "m5.large"}},
+		{Name: "default"},
+	}
+	assert.Equal(t, []string{"g5.xlarge", "m5.large"}, d.requestedInstanceTypes())
+	assert.Equal(t, []string{"m5.large"}, d.InstanceTypes)
+}
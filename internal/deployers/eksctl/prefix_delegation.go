@@ -0,0 +1,119 @@
+package eksctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/klog"
+)
+
+const (
+	// each prefix assigned to an ENI slot is a /28, i.e. 16 addresses
+	addressesPerPrefix = 16
+	// EKS recommends these max pods for prefix delegation, depending on whether the instance has fewer than 30 vCPUs
+	maxPodsSmallInstance = 110
+	maxPodsLargeInstance = 250
+	largeInstanceVCPUs   = 30
+)
+
+// minPrefixDelegationVPCCNIVersion is the first VPC CNI version that supports prefix delegation
+var minPrefixDelegationVPCCNIVersion = version.MustParseGeneric("1.9.0")
+
+// verifyPrefixDelegation checks that the instance types and VPC CNI version support --prefix-delegation,
+// and works out the max pods per node that the nodegroup is rendered with
+func (d *deployer) verifyPrefixDelegation() error {
+	if !d.PrefixDelegation {
+		if d.WarmPrefixTarget != 0 {
+			return fmt.Errorf("--warm-prefix-target requires --prefix-delegation")
+		}
+		return nil
+	}
+	if d.IPv6 {
+		return fmt.Errorf("--prefix-delegation cannot be used with --ipv6, which always assigns prefixes")
+	}
+	if d.WarmPrefixTarget < 0 {
+		return fmt.Errorf("--warm-prefix-target must not be negative")
+	}
+	if d.AMI != "" && !d.UseUnmanagedNodegroup {
+		return fmt.Errorf("--prefix-delegation cannot be used with --ami for managed nodegroups, which don't support setting max pods")
+	}
+	if d.VPCCNIVersion != "" && d.VPCCNIVersion != "latest" {
+		vpcCNIVersion, err := version.ParseGeneric(d.VPCCNIVersion)
+		if err != nil {
+			return fmt.Errorf("--vpc-cni-version is invalid: %v", err)
+		}
+		if !vpcCNIVersion.AtLeast(minPrefixDelegationVPCCNIVersion) {
+			return fmt.Errorf("--prefix-delegation requires --vpc-cni-version %s or later", minPrefixDelegationVPCCNIVersion)
+		}
+	}
+	instanceTypes := d.InstanceTypes
+	if len(instanceTypes) == 0 {
+		instanceTypes = []string{eksctl_api.DefaultNodeType}
+	}
+	out, err := d.ec2Client.DescribeInstanceTypes(context.TODO(), &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: toInstanceTypes(instanceTypes),
+	}, d.ec2Region)
+	if err != nil {
+		return fmt.Errorf("failed to describe instance types %v: %v", instanceTypes, err)
+	}
+	var nonNitroInstanceTypes []string
+	d.maxPodsPerNode = 0
+	for _, instanceType := range out.InstanceTypes {
+		if instanceType.Hypervisor != ec2types.InstanceTypeHypervisorNitro {
+			nonNitroInstanceTypes = append(nonNitroInstanceTypes, string(instanceType.InstanceType))
+			continue
+		}
+		// every instance type in the nodegroup shares its max pods, so use the lowest
+		if maxPods := maxPodsWithPrefixes(instanceType); d.maxPodsPerNode == 0 || maxPods < d.maxPodsPerNode {
+			d.maxPodsPerNode = maxPods
+		}
+	}
+	if len(nonNitroInstanceTypes) > 0 {
+		return fmt.Errorf("--prefix-delegation requires Nitro instance types: %v", nonNitroInstanceTypes)
+	}
+	klog.Infof("Using %d max pods per node for prefix delegation on %v", d.maxPodsPerNode, instanceTypes)
+	return nil
+}
+
+// maxPodsWithPrefixes follows EKS's max pods calculation with prefix delegation:
+// every secondary IP slot of every ENI holds a prefix, plus the host-network pods, capped at the recommended max
+func maxPodsWithPrefixes(instanceType ec2types.InstanceTypeInfo) int {
+	var enis, addressesPerENI, vcpus int
+	if instanceType.NetworkInfo != nil {
+		enis = int(aws.ToInt32(instanceType.NetworkInfo.MaximumNetworkInterfaces))
+		addressesPerENI = int(aws.ToInt32(instanceType.NetworkInfo.Ipv4AddressesPerInterface))
+	}
+	if instanceType.VCpuInfo != nil {
+		vcpus = int(aws.ToInt32(instanceType.VCpuInfo.DefaultVCpus))
+	}
+	maxPods := enis*(addressesPerENI-1)*addressesPerPrefix + 2
+	if vcpus < largeInstanceVCPUs {
+		return min(maxPods, maxPodsSmallInstance)
+	}
+	return min(maxPods, maxPodsLargeInstance)
+}
+
+// vpcCNIConfigurationValues returns the VPC CNI addon's configuration values for --prefix-delegation, or an empty string if it's not set
+func (d *deployer) vpcCNIConfigurationValues() (string, error) {
+	if !d.PrefixDelegation {
+		return "", nil
+	}
+	env := map[string]string{
+		"ENABLE_PREFIX_DELEGATION": "true",
+	}
+	if d.WarmPrefixTarget > 0 {
+		env["WARM_PREFIX_TARGET"] = strconv.Itoa(d.WarmPrefixTarget)
+	}
+	values, err := json.Marshal(map[string]any{"env": env})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal VPC CNI configuration values: %v", err)
+	}
+	return string(values), nil
+}
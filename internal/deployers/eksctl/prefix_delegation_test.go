@@ -0,0 +1,40 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_maxPodsWithPrefixes(t *testing.T) {
+	instanceType := func(vcpus, enis, addressesPerENI int32) ec2types.InstanceTypeInfo {
+		return ec2types.InstanceTypeInfo{
+			VCpuInfo: &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(vcpus)},
+			NetworkInfo: &ec2types.NetworkInfo{
+				MaximumNetworkInterfaces:  aws.Int32(enis),
+				Ipv4AddressesPerInterface: aws.Int32(addressesPerENI),
+			},
+		}
+	}
+	// t3.nano: 2 ENIs with 2 addresses each
+	assert.Equal(t, 34, maxPodsWithPrefixes(instanceType(2, 2, 2)))
+	// m5.large: 3 ENIs with 10 addresses each
+	assert.Equal(t, 110, maxPodsWithPrefixes(instanceType(2, 3, 10)))
+	// m5.8xlarge: 8 ENIs with 30 addresses each
+	assert.Equal(t, 250, maxPodsWithPrefixes(instanceType(32, 8, 30)))
+}
+
+func Test_CreateClusterConfig_prefixDelegation(t *testing.T) {
+	d := &deployer{
+		UpOptions:      &UpOptions{ClusterName: "test", PrefixDelegation: true, WarmPrefixTarget: 2},
+		maxPodsPerNode: 110,
+	}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Addons, 1)
+	assert.Equal(t, "vpc-cni", cfg.Addons[0].Name)
+	assert.JSONEq(t, `{"env":{"ENABLE_PREFIX_DELEGATION":"true","WARM_PREFIX_TARGET":"2"}}`, cfg.Addons[0].ConfigurationValues)
+	assert.Equal(t, 110, cfg.ManagedNodeGroups[0].MaxPodsPerNode)
+}
@@ -0,0 +1,196 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"k8s.io/klog"
+)
+
+// region returns the region the cluster will be created in.
+// The --region flag wins, otherwise we fall back to the region of the AWS SDK config.
+func (d *deployer) region() string {
+	if d.Region != "" {
+		return d.Region
+	}
+	return d.awsConfig.Region
+}
+
+// ec2Region points an EC2 API call at the cluster's region, which may differ from the AWS SDK config's.
+func (d *deployer) ec2Region(o *ec2.Options) {
+	o.Region = d.region()
+}
+
+// eksRegion points an EKS API call at the cluster's region, which may differ from the AWS SDK config's.
+func (d *deployer) eksRegion(o *eks.Options) {
+	o.Region = d.region()
+}
+
+// cloudwatchRegion points a CloudWatch API call at the cluster's region, which may differ from the AWS SDK config's.
+func (d *deployer) cloudwatchRegion(o *cloudwatch.Options) {
+	o.Region = d.region()
+}
+
+// kmsRegion points a KMS API call at the cluster's region, which may differ from the AWS SDK config's.
+func (d *deployer) kmsRegion(o *kms.Options) {
+	o.Region = d.region()
+}
+
+// ssmRegion points a Systems Manager API call at the cluster's region, which may differ from the AWS SDK config's.
+func (d *deployer) ssmRegion(o *ssm.Options) {
+	o.Region = d.region()
+}
+
+// resolveAvailabilityZones sets --availability-zones to the account's names of the --availability-zone-ids, if they're set
+func (d *deployer) resolveAvailabilityZones() error {
+	if len(d.AvailabilityZoneIDs) == 0 {
+		return nil
+	}
+	if len(d.AvailabilityZones) > 0 {
+		return fmt.Errorf("--availability-zones and --availability-zone-ids are mutually exclusive")
+	}
+	availabilityZones, err := d.resolveAvailabilityZoneIDs(d.AvailabilityZoneIDs)
+	if err != nil {
+		return err
+	}
+	d.AvailabilityZones = availabilityZones
+	return nil
+}
+
+// resolveAvailabilityZoneIDs maps availability zone IDs to this account's availability zone names.
+// Zone IDs refer to the same physical zone in every account, while the names are shuffled per account.
+func (d *deployer) resolveAvailabilityZoneIDs(zoneIDs []string) ([]string, error) {
+	out, err := d.ec2Client.DescribeAvailabilityZones(context.TODO(), &ec2.DescribeAvailabilityZonesInput{
+		ZoneIds: zoneIDs,
+	}, d.ec2Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe availability zones %v: %v", zoneIDs, err)
+	}
+	namesByID := make(map[string]string)
+	for _, zone := range out.AvailabilityZones {
+		namesByID[aws.ToString(zone.ZoneId)] = aws.ToString(zone.ZoneName)
+	}
+	var names []string
+	var unknown []string
+	for _, zoneID := range zoneIDs {
+		if name, ok := namesByID[zoneID]; ok {
+			names = append(names, name)
+		} else {
+			unknown = append(unknown, zoneID)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("availability zone IDs do not exist in %s: %v", d.region(), unknown)
+	}
+	klog.Infof("resolved availability zone IDs %v to %v", zoneIDs, names)
+	return names, nil
+}
+
+// verifyInstanceTypeOfferings fails fast if any requested instance type, including those of the --nodegroups-file, isn't offered in the region,
+// or in each of the availability zones when they're pinned.
+// Otherwise, this only surfaces deep inside the CloudFormation stack creation.
+func (d *deployer) verifyInstanceTypeOfferings() error {
+	instanceTypes := d.requestedInstanceTypes()
+	if len(instanceTypes) == 0 {
+		return nil
+	}
+	locationType := ec2types.LocationTypeRegion
+	locations := []string{d.region()}
+	if len(d.AvailabilityZones) > 0 {
+		locationType = ec2types.LocationTypeAvailabilityZone
+		locations = d.AvailabilityZones
+	}
+	klog.Infof("verifying instance types %v are offered in %v", instanceTypes, locations)
+	offered := make(map[string]bool)
+	paginator := ec2.NewDescribeInstanceTypeOfferingsPaginator(d.ec2Client, &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: locationType,
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("instance-type"),
+				Values: instanceTypes,
+			},
+			{
+				Name:   aws.String("location"),
+				Values: locations,
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO(), d.ec2Region)
+		if err != nil {
+			return fmt.Errorf("failed to describe instance type offerings: %v", err)
+		}
+		for _, offering := range page.InstanceTypeOfferings {
+			offered[instanceTypeOfferingKey(string(offering.InstanceType), aws.ToString(offering.Location))] = true
+		}
+	}
+	var unavailable []string
+	for _, instanceType := range instanceTypes {
+		for _, location := range locations {
+			if !offered[instanceTypeOfferingKey(instanceType, location)] {
+				unavailable = append(unavailable, fmt.Sprintf("%s in %s", instanceType, location))
+			}
+		}
+	}
+	if len(unavailable) > 0 {
+		return fmt.Errorf("instance types are not offered: %s", strings.Join(unavailable, ", "))
+	}
+	return nil
+}
+
+// requestedInstanceTypes returns the --instance-types and those of the --nodegroups-file nodegroups, without duplicates
+func (d *deployer) requestedInstanceTypes() []string {
+	instanceTypes := slices.Clone(d.InstanceTypes)
+	for _, spec := range d.nodegroupSpecs {
+		instanceTypes = append(instanceTypes, spec.InstanceTypes...)
+	}
+	slices.Sort(instanceTypes)
+	return slices.Compact(instanceTypes)
+}
+
+func instanceTypeOfferingKey(instanceType string, location string) string {
+	return instanceType + "/" + location
+}
+
+// kubernetesVersionEndOfSupportWarning is how close to its end of support a Kubernetes version can be before we warn about it
+const kubernetesVersionEndOfSupportWarning = 90 * 24 * time.Hour
+
+// verifyKubernetesVersion fails fast if EKS doesn't offer the Kubernetes version in the region
+func (d *deployer) verifyKubernetesVersion() error {
+	klog.Infof("verifying Kubernetes version %s is supported in %s", d.KubernetesVersion, d.region())
+	out, err := d.eksClient.DescribeClusterVersions(context.TODO(), &eks.DescribeClusterVersionsInput{
+		ClusterVersions: []string{d.KubernetesVersion},
+		IncludeAll:      aws.Bool(true),
+	}, d.eksRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe cluster versions: %v", err)
+	}
+	if len(out.ClusterVersions) == 0 {
+		return fmt.Errorf("Kubernetes version %s is not offered by EKS in %s", d.KubernetesVersion, d.region())
+	}
+	version := out.ClusterVersions[0]
+	var endOfSupport *time.Time
+	switch version.Status {
+	case ekstypes.ClusterVersionStatusStandardSupport:
+		endOfSupport = version.EndOfStandardSupportDate
+	case ekstypes.ClusterVersionStatusExtendedSupport:
+		endOfSupport = version.EndOfExtendedSupportDate
+	default:
+		return fmt.Errorf("Kubernetes version %s is no longer supported by EKS (status: %s)", d.KubernetesVersion, version.Status)
+	}
+	if endOfSupport != nil && time.Until(*endOfSupport) < kubernetesVersionEndOfSupportWarning {
+		klog.Warningf("Kubernetes version %s reaches the end of %s on %s", d.KubernetesVersion, version.Status, endOfSupport.Format(time.DateOnly))
+	}
+	return nil
+}
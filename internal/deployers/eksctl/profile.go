@@ -0,0 +1,79 @@
+package eksctl
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+)
+
+// nodeProfiles is the format of the --profiles-file, a set of named node hardware profiles:
+//
+//	profiles:
+//	  gpu-large:
+//	    nodeAMIType: AL2023_x86_64_NVIDIA
+//	    instanceTypes: [g5.12xlarge]
+//	    volumeSize: 200
+type nodeProfiles struct {
+	Profiles map[string]nodeProfile `json:"profiles"`
+}
+
+type nodeProfile struct {
+	AMI           string   `json:"ami"`
+	AMIFamily     string   `json:"amiFamily"`
+	NodeAMIType   string   `json:"nodeAMIType"`
+	InstanceTypes []string `json:"instanceTypes"`
+	Nodes         int      `json:"nodes"`
+	VolumeSize    int      `json:"volumeSize"`
+	EFAEnabled    bool     `json:"efaEnabled"`
+}
+
+// applyProfile loads the --profile from the --profiles-file and fills in the options whose flags weren't set
+func (d *deployer) applyProfile() error {
+	if d.ProfilesFile == "" {
+		return fmt.Errorf("--profile requires --profiles-file")
+	}
+	data, err := os.ReadFile(d.ProfilesFile)
+	if err != nil {
+		return fmt.Errorf("failed to read --profiles-file: %v", err)
+	}
+	var profiles nodeProfiles
+	if err := yaml.UnmarshalStrict(data, &profiles); err != nil {
+		return fmt.Errorf("failed to parse --profiles-file %s: %v", d.ProfilesFile, err)
+	}
+	profile, ok := profiles.Profiles[d.Profile]
+	if !ok {
+		var names []string
+		for name := range profiles.Profiles {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return fmt.Errorf("profile %s not found in %s, available profiles: %v", d.Profile, d.ProfilesFile, names)
+	}
+	klog.Infof("Using profile %s: %+v", d.Profile, profile)
+	// a flag that was set takes precedence even if set to its zero value, e.g. --nodes=0 or --efa-enabled=false
+	if !d.flagChanged("ami") && profile.AMI != "" {
+		d.AMI = profile.AMI
+	}
+	if !d.flagChanged("ami-family") && profile.AMIFamily != "" {
+		d.AMIFamily = profile.AMIFamily
+	}
+	if !d.flagChanged("node-ami-type") && profile.NodeAMIType != "" {
+		d.NodeAMIType = profile.NodeAMIType
+	}
+	if !d.flagChanged("instance-types") && len(profile.InstanceTypes) > 0 {
+		d.InstanceTypes = profile.InstanceTypes
+	}
+	if !d.flagChanged("nodes") && profile.Nodes != 0 {
+		d.Nodes = profile.Nodes
+	}
+	if !d.flagChanged("volume-size") && profile.VolumeSize != 0 {
+		d.VolumeSize = profile.VolumeSize
+	}
+	if !d.flagChanged("efa-enabled") {
+		d.EFAEnabled = profile.EFAEnabled
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+func Test_applyProfile(t *testing.T) {
+	profilesFile := filepath.Join(t.TempDir(), "profiles.yaml")
+	assert.NoError(t, os.WriteFile(profilesFile, []byte(`profiles:
+  gpu-large:
+    nodeAMIType: AL2023_x86_64_NVIDIA
+    instanceTypes: [g5.12xlarge]
+    volumeSize: 200
+    nodes: 2
+    efaEnabled: true
+`), 0644))
+
+	d := &deployer{UpOptions: &UpOptions{Profile: "gpu-large", ProfilesFile: profilesFile}}
+	flags, err := gpflag.Parse(d)
+	assert.NoError(t, err)
+	d.flags = flags
+	assert.NoError(t, flags.Set("volume-size", "500"))
+	// flags set to their zero values also win
+	assert.NoError(t, flags.Set("nodes", "0"))
+	assert.NoError(t, flags.Set("efa-enabled", "false"))
+	assert.NoError(t, d.applyProfile())
+	assert.Equal(t, "AL2023_x86_64_NVIDIA", d.NodeAMIType)
+	assert.Equal(t, []string{"g5.12xlarge"}, d.InstanceTypes)
+	// explicit flags win
+	assert.Equal(t, 500, d.VolumeSize)
+	assert.Equal(t, 0, d.Nodes)
+	assert.False(t, d.EFAEnabled)
+
+	d = &deployer{UpOptions: &UpOptions{Profile: "cpu-small", ProfilesFile: profilesFile}}
+	assert.ErrorContains(t, d.applyProfile(), "profile cpu-small not found")
+}
+
+func Test_applyProfile_unsetFlags(t *testing.T) {
+	profilesFile := filepath.Join(t.TempDir(), "profiles.yaml")
+	assert.NoError(t, os.WriteFile(profilesFile, []byte(`profiles:
+  efa:
+    nodes: 2
+    efaEnabled: true
+`), 0644))
+
+	d := &deployer{UpOptions: &UpOptions{Profile: "efa", ProfilesFile: profilesFile}}
+	assert.NoError(t, d.applyProfile())
+	assert.Equal(t, 2, d.Nodes)
+	assert.True(t, d.EFAEnabled)
+}
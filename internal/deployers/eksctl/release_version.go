@@ -0,0 +1,51 @@
+package eksctl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var (
+	// amazonLinuxReleaseVersionPattern matches EKS-optimized Amazon Linux AMI releases, e.g. 1.33.0-20250704
+	amazonLinuxReleaseVersionPattern = regexp.MustCompile(`^(\d+\.\d+)\.\d+-\d{8}$`)
+	// bottlerocketReleaseVersionPattern matches Bottlerocket releases, e.g. 1.42.0-1c2d3e4f
+	bottlerocketReleaseVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9a-f]{8})?$`)
+)
+
+// verifyNodeReleaseVersion checks that --nodegroup-release-version is an AMI release of the nodegroup's AMI family,
+// and for Amazon Linux, that it was built for --kubernetes-version
+func (d *deployer) verifyNodeReleaseVersion() error {
+	if d.NodeReleaseVersion == "" {
+		return nil
+	}
+	if d.UseUnmanagedNodegroup {
+		return fmt.Errorf("--nodegroup-release-version requires a managed nodegroup")
+	}
+	if d.AMI != "" {
+		return fmt.Errorf("--nodegroup-release-version cannot be used with --ami")
+	}
+	amiFamily := d.AMIFamily
+	if amiFamily == "" {
+		amiFamily = eksctl_api.NodeImageFamilyAmazonLinux2
+	}
+	switch amiFamily {
+	case eksctl_api.NodeImageFamilyAmazonLinux2, eksctl_api.NodeImageFamilyAmazonLinux2023:
+		match := amazonLinuxReleaseVersionPattern.FindStringSubmatch(d.NodeReleaseVersion)
+		if match == nil {
+			return fmt.Errorf("--nodegroup-release-version for %s must be an EKS-optimized AMI release like 1.33.0-20250704: %s", amiFamily, d.NodeReleaseVersion)
+		}
+		if d.KubernetesVersion != "" && match[1] != strings.TrimPrefix(d.KubernetesVersion, "v") {
+			return fmt.Errorf("--nodegroup-release-version %s is for Kubernetes %s, but --kubernetes-version is %s", d.NodeReleaseVersion, match[1], d.KubernetesVersion)
+		}
+	case eksctl_api.NodeImageFamilyBottlerocket:
+		if !bottlerocketReleaseVersionPattern.MatchString(d.NodeReleaseVersion) {
+			return fmt.Errorf("--nodegroup-release-version for %s must be a Bottlerocket release like 1.42.0-1c2d3e4f: %s", amiFamily, d.NodeReleaseVersion)
+		}
+	default:
+		return fmt.Errorf("--nodegroup-release-version is only supported for the %s, %s, and %s AMI families", eksctl_api.NodeImageFamilyAmazonLinux2, eksctl_api.NodeImageFamilyAmazonLinux2023, eksctl_api.NodeImageFamilyBottlerocket)
+	}
+	return nil
+}
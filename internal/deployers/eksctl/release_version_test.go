@@ -0,0 +1,55 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyNodeReleaseVersion(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    UpOptions
+		wantErr string
+	}{
+		{
+			name: "amazon linux",
+			opts: UpOptions{NodeReleaseVersion: "1.33.0-20250704", KubernetesVersion: "1.33", AMIFamily: "AmazonLinux2023"},
+		},
+		{
+			name:    "amazon linux for another kubernetes version",
+			opts:    UpOptions{NodeReleaseVersion: "1.32.3-20250704", KubernetesVersion: "1.33"},
+			wantErr: "is for Kubernetes 1.32, but --kubernetes-version is 1.33",
+		},
+		{
+			name:    "bottlerocket release for amazon linux",
+			opts:    UpOptions{NodeReleaseVersion: "1.42.0-1c2d3e4f", KubernetesVersion: "1.33"},
+			wantErr: "must be an EKS-optimized AMI release",
+		},
+		{
+			name: "bottlerocket",
+			opts: UpOptions{NodeReleaseVersion: "1.42.0-1c2d3e4f", AMIFamily: "Bottlerocket"},
+		},
+		{
+			name:    "unmanaged nodegroup",
+			opts:    UpOptions{NodeReleaseVersion: "1.33.0-20250704", UseUnmanagedNodegroup: true},
+			wantErr: "requires a managed nodegroup",
+		},
+		{
+			name:    "unsupported family",
+			opts:    UpOptions{NodeReleaseVersion: "1.33.0-20250704", AMIFamily: "Ubuntu2404"},
+			wantErr: "only supported for",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := &deployer{UpOptions: &c.opts}
+			err := d.verifyNodeReleaseVersion()
+			if c.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package eksctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/klog"
+)
+
+// kmsKeyPendingWindowDays is how long a --create-kms-key key waits before it's deleted, the shortest KMS allows
+const kmsKeyPendingWindowDays = 7
+
+// kmsKeyAlias names the key created by --create-kms-key, so Down can find it without state from Up
+func (d *deployer) kmsKeyAlias() string {
+	return fmt.Sprintf("alias/kubetest2-eksctl/%s", d.clusterName)
+}
+
+// verifySecretsEncryption checks that the --kms-key-arn is an enabled KMS key in the cluster's region
+func (d *deployer) verifySecretsEncryption() error {
+	if d.KMSKeyARN == "" && !d.CreateKMSKey {
+		return nil
+	}
+	if d.KMSKeyARN != "" && d.CreateKMSKey {
+		return fmt.Errorf("--kms-key-arn and --create-kms-key are mutually exclusive")
+	}
+	if d.DeployTarget != "cluster" {
+		return fmt.Errorf("--kms-key-arn and --create-kms-key require --deploy-target=cluster, secrets encryption is enabled when the cluster is created")
+	}
+	if d.CreateKMSKey {
+		return nil
+	}
+	keyARN, err := arn.Parse(d.KMSKeyARN)
+	if err != nil || keyARN.Service != "kms" {
+		return fmt.Errorf("--kms-key-arn must be the ARN of a KMS key: %s", d.KMSKeyARN)
+	}
+	if keyARN.Region != d.region() {
+		return fmt.Errorf("--kms-key-arn must be in the cluster's region %s: %s", d.region(), d.KMSKeyARN)
+	}
+	out, err := d.kmsClient.DescribeKey(context.TODO(), &kms.DescribeKeyInput{
+		KeyId: aws.String(d.KMSKeyARN),
+	}, d.kmsRegion)
+	if err != nil {
+		return fmt.Errorf("failed to describe --kms-key-arn %s: %v", d.KMSKeyARN, err)
+	}
+	if state := out.KeyMetadata.KeyState; state != kmstypes.KeyStateEnabled {
+		return fmt.Errorf("--kms-key-arn %s is %s, not %s", d.KMSKeyARN, state, kmstypes.KeyStateEnabled)
+	}
+	return nil
+}
+
+// createKMSKey creates a customer managed key for --create-kms-key, aliased for the cluster, and sets it as the --kms-key-arn
+func (d *deployer) createKMSKey(ctx context.Context) error {
+	out, err := d.kmsClient.CreateKey(ctx, &kms.CreateKeyInput{
+		Description: aws.String(fmt.Sprintf("kubetest2-eksctl secrets encryption key for cluster %s", d.clusterName)),
+		Tags: []kmstypes.Tag{
+			{TagKey: aws.String(fmt.Sprintf("kubernetes.io/cluster/%s", d.clusterName)), TagValue: aws.String("owned")},
+		},
+	}, d.kmsRegion)
+	if err != nil {
+		return fmt.Errorf("failed to create KMS key: %v", err)
+	}
+	keyARN := aws.ToString(out.KeyMetadata.Arn)
+	if _, err := d.kmsClient.CreateAlias(ctx, &kms.CreateAliasInput{
+		AliasName:   aws.String(d.kmsKeyAlias()),
+		TargetKeyId: out.KeyMetadata.KeyId,
+	}, d.kmsRegion); err != nil {
+		return errors.Join(fmt.Errorf("failed to create alias %s for KMS key %s: %v", d.kmsKeyAlias(), keyARN, err), d.deleteKMSKey(ctx, keyARN))
+	}
+	klog.Infof("Created KMS key %s (%s) for secrets encryption", keyARN, d.kmsKeyAlias())
+	d.KMSKeyARN = keyARN
+	return nil
+}
+
+// setSecretsEncryption renders the --kms-key-arn, with which EKS envelope encrypts the cluster's secrets
+func (d *deployer) setSecretsEncryption(cfg *eksctl_api.ClusterConfig) {
+	if d.KMSKeyARN == "" {
+		return
+	}
+	cfg.SecretsEncryption = &eksctl_api.SecretsEncryption{KeyARN: d.KMSKeyARN}
+}
+
+// scheduleKMSKeyDeletion schedules the deletion of the --create-kms-key key, after the cluster that used it is deleted
+func (d *deployer) scheduleKMSKeyDeletion(ctx context.Context) error {
+	out, err := d.kmsClient.DescribeKey(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(d.kmsKeyAlias()),
+	}, d.kmsRegion)
+	if err != nil {
+		var notFound *kmstypes.NotFoundException
+		if errors.As(err, &notFound) {
+			klog.Infof("KMS key %s does not exist, nothing to delete", d.kmsKeyAlias())
+			return nil
+		}
+		return fmt.Errorf("failed to describe KMS key %s: %v", d.kmsKeyAlias(), err)
+	}
+	if _, err := d.kmsClient.DeleteAlias(ctx, &kms.DeleteAliasInput{
+		AliasName: aws.String(d.kmsKeyAlias()),
+	}, d.kmsRegion); err != nil {
+		return fmt.Errorf("failed to delete alias %s: %v", d.kmsKeyAlias(), err)
+	}
+	return d.deleteKMSKey(ctx, aws.ToString(out.KeyMetadata.Arn))
+}
+
+func (d *deployer) deleteKMSKey(ctx context.Context, keyARN string) error {
+	out, err := d.kmsClient.ScheduleKeyDeletion(ctx, &kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(keyARN),
+		PendingWindowInDays: aws.Int32(kmsKeyPendingWindowDays),
+	}, d.kmsRegion)
+	if err != nil {
+		return fmt.Errorf("failed to schedule deletion of KMS key %s: %v", keyARN, err)
+	}
+	klog.Infof("Scheduled deletion of KMS key %s on %v", keyARN, aws.ToTime(out.DeletionDate))
+	return nil
+}
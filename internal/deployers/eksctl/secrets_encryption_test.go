@@ -0,0 +1,37 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifySecretsEncryption(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{Region: "us-west-2", DeployTarget: "cluster", CreateKMSKey: true}}
+	assert.NoError(t, d.verifySecretsEncryption())
+
+	d.KMSKeyARN = "arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	assert.ErrorContains(t, d.verifySecretsEncryption(), "--kms-key-arn and --create-kms-key are mutually exclusive")
+
+	d.CreateKMSKey = false
+	d.KMSKeyARN = "arn:aws:iam::123456789012:role/e2e"
+	assert.ErrorContains(t, d.verifySecretsEncryption(), "--kms-key-arn must be the ARN of a KMS key")
+	d.KMSKeyARN = "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	assert.ErrorContains(t, d.verifySecretsEncryption(), "--kms-key-arn must be in the cluster's region us-west-2")
+
+	d.DeployTarget = "nodegroup"
+	assert.ErrorContains(t, d.verifySecretsEncryption(), "require --deploy-target=cluster")
+}
+
+func Test_CreateClusterConfig_secretsEncryption(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test"}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, cfg.SecretsEncryption)
+	assert.Equal(t, "alias/kubetest2-eksctl/test", d.kmsKeyAlias())
+
+	d.KMSKeyARN = "arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, d.KMSKeyARN, cfg.SecretsEncryption.KeyARN)
+}
@@ -0,0 +1,40 @@
+package eksctl
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// verifySpot checks --spot-instance-types, which take the place of --instance-types for the Spot nodegroup,
+// so this runs before the instance types are checked
+func (d *deployer) verifySpot() error {
+	if len(d.SpotInstanceTypes) == 0 {
+		return nil
+	}
+	if !d.Spot {
+		return fmt.Errorf("--spot-instance-types requires --spot")
+	}
+	if len(d.InstanceTypes) > 0 {
+		return fmt.Errorf("--spot-instance-types cannot be used with --instance-types")
+	}
+	d.InstanceTypes = d.SpotInstanceTypes
+	return nil
+}
+
+// setSpotInstancesDistribution renders an unmanaged nodegroup that launches only Spot instances of the instance types,
+// picking the pools that are least likely to be interrupted
+func setSpotInstancesDistribution(ng *eksctl_api.NodeGroup, instanceTypes []string) {
+	if len(instanceTypes) == 0 {
+		instanceTypes = []string{eksctl_api.DefaultNodeType}
+	}
+	// eksctl requires the instance type to be unset for a mixed instances nodegroup
+	ng.InstanceType = ""
+	ng.InstancesDistribution = &eksctl_api.NodeGroupInstancesDistribution{
+		InstanceTypes:                       instanceTypes,
+		OnDemandBaseCapacity:                aws.Int(0),
+		OnDemandPercentageAboveBaseCapacity: aws.Int(0),
+		SpotAllocationStrategy:              aws.String(eksctl_api.SpotAllocationStrategyCapacityOptimized),
+	}
+}
@@ -0,0 +1,42 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+func Test_verifySpot(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{Spot: true, SpotInstanceTypes: []string{"m5.large", "m6i.large"}}}
+	assert.NoError(t, d.verifySpot())
+	assert.Equal(t, []string{"m5.large", "m6i.large"}, d.InstanceTypes)
+
+	d = &deployer{UpOptions: &UpOptions{SpotInstanceTypes: []string{"m5.large"}}}
+	assert.ErrorContains(t, d.verifySpot(), "--spot-instance-types requires --spot")
+
+	d = &deployer{UpOptions: &UpOptions{Spot: true, SpotInstanceTypes: []string{"m5.large"}, InstanceTypes: []string{"c5.large"}}}
+	assert.ErrorContains(t, d.verifySpot(), "cannot be used with --instance-types")
+}
+
+func Test_CreateClusterConfig_spot(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{
+		ClusterName:   "test",
+		Spot:          true,
+		InstanceTypes: []string{"m5.large", "m6i.large"},
+	}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.True(t, cfg.ManagedNodeGroups[0].Spot)
+	assert.Equal(t, []string{"m5.large", "m6i.large"}, cfg.ManagedNodeGroups[0].InstanceTypes)
+
+	d.UseUnmanagedNodegroup = true
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	ng := cfg.NodeGroups[0]
+	assert.Empty(t, ng.InstanceType)
+	assert.Equal(t, []string{"m5.large", "m6i.large"}, ng.InstancesDistribution.InstanceTypes)
+	assert.Equal(t, 0, *ng.InstancesDistribution.OnDemandBaseCapacity)
+	assert.Equal(t, 0, *ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity)
+	assert.Equal(t, eksctl_api.SpotAllocationStrategyCapacityOptimized, *ng.InstancesDistribution.SpotAllocationStrategy)
+}
@@ -0,0 +1,109 @@
+package eksctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+// ssmRegistrationTimeout is how long the nodegroup's instances may take to come online in Systems Manager with --enable-ssm
+const ssmRegistrationTimeout = 10 * time.Minute
+
+// isSSHPublicKeyFile reports whether --ssh-public-key is a local public key file, rather than the name of an EC2 key pair
+func isSSHPublicKeyFile(sshPublicKey string) bool {
+	info, err := os.Stat(sshPublicKey)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// verifySSHPublicKey checks that --ssh-public-key is a public key file or an existing EC2 key pair, the same as eksctl's flag of that name
+func (d *deployer) verifySSHPublicKey() error {
+	if d.SSHPublicKey == "" || isSSHPublicKeyFile(d.SSHPublicKey) {
+		return nil
+	}
+	_, err := d.ec2Client.DescribeKeyPairs(context.TODO(), &ec2.DescribeKeyPairsInput{
+		KeyNames: []string{d.SSHPublicKey},
+	}, d.ec2Region)
+	if err != nil {
+		return fmt.Errorf("--ssh-public-key is neither a public key file nor an EC2 key pair in %s: %v", d.region(), err)
+	}
+	return nil
+}
+
+// setSSHAccess renders the --ssh-public-key into the nodegroup's ssh config, which opens port 22 on the nodes.
+// Without it, the nodegroup has no SSH access, and eksctl would otherwise look for ~/.ssh/id_rsa.pub.
+func (d *deployer) setSSHAccess(ng *eksctl_api.NodeGroupBase) {
+	if d.SSHPublicKey == "" {
+		ng.SSH = nil
+		return
+	}
+	ng.SSH = &eksctl_api.NodeGroupSSH{Allow: aws.Bool(true)}
+	if isSSHPublicKeyFile(d.SSHPublicKey) {
+		ng.SSH.PublicKeyPath = aws.String(d.SSHPublicKey)
+	} else {
+		ng.SSH.PublicKeyName = aws.String(d.SSHPublicKey)
+	}
+}
+
+// waitForSSMRegistration waits for the nodegroup's instances to be online in Systems Manager, so Session Manager and Run Command
+// can reach them for debugging and log collection. eksctl attaches the SSM managed policy to the node role by default.
+func (d *deployer) waitForSSMRegistration(ctx context.Context) error {
+	instanceIDs, err := d.getNodegroupInstanceIDs()
+	if err != nil {
+		return err
+	}
+	klog.Infof("Waiting up to %v for instances %v to come online in Systems Manager", ssmRegistrationTimeout, instanceIDs)
+	err = wait.PollUntilContextTimeout(ctx, 15*time.Second, ssmRegistrationTimeout, true, func(ctx context.Context) (bool, error) {
+		online, err := d.ssmOnlineInstanceIDs(ctx)
+		if err != nil {
+			klog.Warningf("failed to describe Systems Manager instances: %v", err)
+			return false, nil
+		}
+		var offline []string
+		for _, instanceID := range instanceIDs {
+			if !slices.Contains(online, instanceID) {
+				offline = append(offline, instanceID)
+			}
+		}
+		klog.Infof("%d/%d instances are online in Systems Manager", len(instanceIDs)-len(offline), len(instanceIDs))
+		return len(offline) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("instances did not come online in Systems Manager: %v", err)
+	}
+	return nil
+}
+
+// ssmOnlineInstanceIDs returns the cluster's instances that are online in Systems Manager
+func (d *deployer) ssmOnlineInstanceIDs(ctx context.Context) ([]string, error) {
+	var online []string
+	paginator := ssm.NewDescribeInstanceInformationPaginator(d.ssmClient, &ssm.DescribeInstanceInformationInput{
+		Filters: []ssmtypes.InstanceInformationStringFilter{
+			{
+				Key:    aws.String(fmt.Sprintf("tag:kubernetes.io/cluster/%s", d.clusterName)),
+				Values: []string{"owned"},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx, d.ssmRegion)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range page.InstanceInformationList {
+			if instance.PingStatus == ssmtypes.PingStatusOnline {
+				online = append(online, aws.ToString(instance.InstanceId))
+			}
+		}
+	}
+	return online, nil
+}
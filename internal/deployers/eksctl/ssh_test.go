@@ -0,0 +1,33 @@
+package eksctl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateClusterConfig_sshPublicKey(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Nodes: 2}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, cfg.ManagedNodeGroups[0].SSH)
+
+	d.SSHPublicKey = "e2e-key-pair"
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.True(t, *cfg.ManagedNodeGroups[0].SSH.Allow)
+	assert.Equal(t, "e2e-key-pair", *cfg.ManagedNodeGroups[0].SSH.PublicKeyName)
+	assert.Nil(t, cfg.ManagedNodeGroups[0].SSH.PublicKeyPath)
+
+	publicKeyPath := filepath.Join(t.TempDir(), "id_ed25519.pub")
+	assert.NoError(t, os.WriteFile(publicKeyPath, []byte("ssh-ed25519 AAAA e2e"), 0644))
+	d.SSHPublicKey = publicKeyPath
+	d.UseUnmanagedNodegroup = true
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.True(t, *cfg.NodeGroups[0].SSH.Allow)
+	assert.Equal(t, publicKeyPath, *cfg.NodeGroups[0].SSH.PublicKeyPath)
+	assert.Nil(t, cfg.NodeGroups[0].SSH.PublicKeyName)
+}
@@ -0,0 +1,83 @@
+package eksctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/kubetest2/pkg/artifacts"
+)
+
+const stackEventsDir = "cloudformation-stack-events"
+
+func (d *deployer) cfnRegion(o *cloudformation.Options) {
+	o.Region = d.region()
+}
+
+// withStackEvents dumps the events of the cluster's remaining eksctl stacks to the artifacts directory,
+// and points the deletion error at them, since they usually explain why deletion stalled
+func (d *deployer) withStackEvents(deleteErr error) error {
+	outputDir := filepath.Join(artifacts.BaseDir(), stackEventsDir)
+	stackNames, err := d.dumpStackEvents(outputDir)
+	if err != nil {
+		return fmt.Errorf("%v (failed to dump CloudFormation stack events: %v)", deleteErr, err)
+	}
+	if len(stackNames) == 0 {
+		return deleteErr
+	}
+	return fmt.Errorf("%v, events of the remaining CloudFormation stacks %v were written to %s", deleteErr, stackNames, outputDir)
+}
+
+// dumpStackEvents writes the events of each eksctl stack of the cluster that hasn't been deleted to outputDir
+func (d *deployer) dumpStackEvents(outputDir string) ([]string, error) {
+	prefix := fmt.Sprintf("eksctl-%s-", d.clusterName)
+	var stackNames []string
+	paginator := cloudformation.NewListStacksPaginator(d.cfnClient, &cloudformation.ListStacksInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO(), d.cfnRegion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stacks: %v", err)
+		}
+		for _, stack := range page.StackSummaries {
+			if stack.StackStatus != cfntypes.StackStatusDeleteComplete && strings.HasPrefix(aws.ToString(stack.StackName), prefix) {
+				stackNames = append(stackNames, aws.ToString(stack.StackName))
+			}
+		}
+	}
+	if len(stackNames) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create stack events directory: %v", err)
+	}
+	for _, stackName := range stackNames {
+		var events []cfntypes.StackEvent
+		eventsPaginator := cloudformation.NewDescribeStackEventsPaginator(d.cfnClient, &cloudformation.DescribeStackEventsInput{
+			StackName: aws.String(stackName),
+		})
+		for eventsPaginator.HasMorePages() {
+			page, err := eventsPaginator.NextPage(context.TODO(), d.cfnRegion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe events of stack %s: %v", stackName, err)
+			}
+			events = append(events, page.StackEvents...)
+		}
+		content, err := json.MarshalIndent(events, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal events of stack %s: %v", stackName, err)
+		}
+		outputPath := filepath.Join(outputDir, stackName+".json")
+		if err := os.WriteFile(outputPath, content, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", outputPath, err)
+		}
+		klog.Infof("wrote CloudFormation stack events: %s", outputPath)
+	}
+	return stackNames, nil
+}
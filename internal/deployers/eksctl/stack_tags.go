@@ -0,0 +1,63 @@
+package eksctl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// CloudFormation's limits on stack tags, which are also AWS's limits on resource tags
+	maxStackTags      = 50
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// tagPattern matches the characters AWS allows in tag keys and values
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// parseStackTags parses --tags into the cluster's metadata.tags, which eksctl applies to each CloudFormation stack it creates.
+// CloudFormation propagates stack tags to the resources in the stack that support tags.
+func parseStackTags(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	if len(pairs) > maxStackTags {
+		return nil, fmt.Errorf("--tags may have at most %d tags", maxStackTags)
+	}
+	tags := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("--tags expected key=value pair: %s", pair)
+		}
+		if err := verifyTag("--tags", key, value); err != nil {
+			return nil, err
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// verifyTag checks a tag from the flag against AWS's constraints on tags, so it's rejected before any CloudFormation stack is created
+func verifyTag(flag, key, value string) error {
+	if key == "" {
+		return fmt.Errorf("%s key must not be empty", flag)
+	}
+	if strings.HasPrefix(strings.ToLower(key), "aws:") {
+		return fmt.Errorf("%s key must not start with the reserved prefix 'aws:': %s", flag, key)
+	}
+	if len(key) > maxTagKeyLength {
+		return fmt.Errorf("%s key must be at most %d characters: %s", flag, maxTagKeyLength, key)
+	}
+	if !tagPattern.MatchString(key) {
+		return fmt.Errorf("%s key may only contain letters, digits, spaces, and _.:/=+-@: %s", flag, key)
+	}
+	if len(value) > maxTagValueLength {
+		return fmt.Errorf("%s value for %s must be at most %d characters", flag, key, maxTagValueLength)
+	}
+	if !tagPattern.MatchString(value) {
+		return fmt.Errorf("%s value for %s may only contain letters, digits, spaces, and _.:/=+-@: %q", flag, key, value)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package eksctl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseStackTags(t *testing.T) {
+	tags, err := parseStackTags([]string{"owner=team-a", "cost-center=1234", "empty="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "team-a", "cost-center": "1234", "empty": ""}, tags)
+
+	tags, err = parseStackTags(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, tags)
+
+	_, err = parseStackTags([]string{"owner"})
+	assert.ErrorContains(t, err, "expected key=value pair")
+	_, err = parseStackTags([]string{"aws:cloudformation:stack-name=test"})
+	assert.ErrorContains(t, err, "reserved prefix")
+	_, err = parseStackTags([]string{"owner=" + strings.Repeat("a", 257)})
+	assert.ErrorContains(t, err, "at most 256 characters")
+	_, err = parseStackTags([]string{"owner=team#a"})
+	assert.ErrorContains(t, err, "--tags value for owner may only contain")
+	_, err = parseStackTags([]string{"cost*center=1234"})
+	assert.ErrorContains(t, err, "--tags key may only contain letters, digits, spaces, and _.:/=+-@: cost*center")
+}
+
+func Test_CreateClusterConfig_stackTags(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Tags: []string{"owner=team-a"}}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "team-a"}, cfg.Metadata.Tags)
+}
+
+func Test_CreateClusterConfig_nodegroupRunTags(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{ClusterName: "test", Tags: []string{"owner=team-a", "team=stack"}}}
+	d.nodegroupTags = map[string]string{"team": "node"}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "team-a", "team": "node"}, cfg.ManagedNodeGroups[0].Tags)
+}
+
+func Test_hasTags(t *testing.T) {
+	tags := map[string]string{"owner": "team-a", "alpha.eksctl.io/cluster-name": "test"}
+	assert.True(t, hasTags(tags, map[string]string{"owner": "team-a"}))
+	assert.False(t, hasTags(tags, map[string]string{"owner": "team-b"}))
+	assert.False(t, hasTags(tags, map[string]string{"owner": "team-a", "run": "1"}))
+}
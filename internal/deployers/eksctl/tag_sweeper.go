@@ -0,0 +1,131 @@
+package eksctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/klog"
+)
+
+// sweptStackDeleteTimeout is how long each leftover stack of the run may take to delete
+const sweptStackDeleteTimeout = 20 * time.Minute
+
+// sweepTaggedResources looks for the run's resources that are left after the cluster or nodegroups are deleted, by the run's --tags.
+// The eksctl stacks that still exist, e.g. after a DELETE_FAILED, are deleted again, and any other EC2 resources
+// with the tags are reported, so they can be attributed to the run and cleaned up.
+// With nodegroupNames, only the stacks of those nodegroups are swept, since the cluster itself isn't the run's to delete.
+func (d *deployer) sweepTaggedResources(ctx context.Context, nodegroupNames []string /* nillable */) error {
+	runTags, err := parseStackTags(d.Tags)
+	if err != nil || len(runTags) == 0 {
+		return err
+	}
+	stackNames, err := d.taggedStackNames(ctx, runTags, nodegroupNames)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	waiter := cloudformation.NewStackDeleteCompleteWaiter(d.cfnClient)
+	for _, stackName := range stackNames {
+		klog.Infof("deleting leftover stack %s of cluster %s", stackName, d.clusterName)
+		if _, err := d.cfnClient.DeleteStack(ctx, &cloudformation.DeleteStackInput{
+			StackName: aws.String(stackName),
+		}, d.cfnRegion); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete leftover stack %s: %v", stackName, err))
+			continue
+		}
+		if err := waiter.Wait(ctx, &cloudformation.DescribeStacksInput{
+			StackName: aws.String(stackName),
+		}, sweptStackDeleteTimeout, func(o *cloudformation.StackDeleteCompleteWaiterOptions) {
+			o.ClientOptions = append(o.ClientOptions, d.cfnRegion)
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("leftover stack %s was not deleted: %v", stackName, err))
+		}
+	}
+	resourceIDs, err := d.taggedEC2ResourceIDs(ctx, runTags)
+	if err != nil {
+		errs = append(errs, err)
+	} else if len(resourceIDs) > 0 {
+		klog.Warningf("EC2 resources with the run's --tags remain after deleting from cluster %s: %v", d.clusterName, resourceIDs)
+	}
+	return errors.Join(errs...)
+}
+
+// taggedStackNames returns the cluster's eksctl stacks with all of the run's tags that haven't been deleted,
+// limited to the stacks of nodegroupNames if there are any
+func (d *deployer) taggedStackNames(ctx context.Context, runTags map[string]string, nodegroupNames []string /* nillable */) ([]string, error) {
+	var stackNames []string
+	paginator := cloudformation.NewDescribeStacksPaginator(d.cfnClient, &cloudformation.DescribeStacksInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx, d.cfnRegion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe stacks: %v", err)
+		}
+		for _, stack := range page.Stacks {
+			if stack.StackStatus == cfntypes.StackStatusDeleteComplete {
+				continue
+			}
+			stackTags := make(map[string]string)
+			for _, tag := range stack.Tags {
+				stackTags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			if len(nodegroupNames) > 0 && !slices.Contains(nodegroupNames, stackTags[eksctl_api.NodeGroupNameTag]) {
+				continue
+			}
+			if stackTags[eksctl_api.ClusterNameTag] == d.clusterName && hasTags(stackTags, runTags) {
+				stackNames = append(stackNames, aws.ToString(stack.StackName))
+			}
+		}
+	}
+	return stackNames, nil
+}
+
+// taggedEC2ResourceIDs returns the IDs of the EC2 resources with all of the run's tags
+func (d *deployer) taggedEC2ResourceIDs(ctx context.Context, runTags map[string]string) ([]string, error) {
+	var resourceIDs []string
+	first := true
+	for key, value := range runTags {
+		var taggedIDs []string
+		paginator := ec2.NewDescribeTagsPaginator(d.ec2Client, &ec2.DescribeTagsInput{
+			Filters: []ec2types.Filter{
+				{Name: aws.String("key"), Values: []string{key}},
+				{Name: aws.String("value"), Values: []string{value}},
+			},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx, d.ec2Region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe EC2 resources tagged %s=%s: %v", key, value, err)
+			}
+			for _, tag := range page.Tags {
+				taggedIDs = append(taggedIDs, aws.ToString(tag.ResourceId))
+			}
+		}
+		if first {
+			resourceIDs = taggedIDs
+			first = false
+			continue
+		}
+		resourceIDs = slices.DeleteFunc(resourceIDs, func(resourceID string) bool { return !slices.Contains(taggedIDs, resourceID) })
+	}
+	slices.Sort(resourceIDs)
+	return resourceIDs, nil
+}
+
+// hasTags reports whether tags includes each of the wanted tags
+func hasTags(tags, wanted map[string]string) bool {
+	for key, value := range wanted {
+		if tagValue, ok := tags[key]; !ok || tagValue != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package eksctl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCloudFormation serves DescribeStacks from stacks, split into pages of one stack
+type fakeCloudFormation struct {
+	cloudformationAPI
+	stacks []cfntypes.Stack
+}
+
+func (f *fakeCloudFormation) DescribeStacks(_ context.Context, params *cloudformation.DescribeStacksInput, _ ...func(*cloudformation.Options)) (*cloudformation.DescribeStacksOutput, error) {
+	i := 0
+	if params.NextToken != nil {
+		for i < len(f.stacks) && aws.ToString(f.stacks[i].StackName) != aws.ToString(params.NextToken) {
+			i++
+		}
+	}
+	out := &cloudformation.DescribeStacksOutput{Stacks: f.stacks[i : i+1]}
+	if i+1 < len(f.stacks) {
+		out.NextToken = f.stacks[i+1].StackName
+	}
+	return out, nil
+}
+
+func testStack(name string, status cfntypes.StackStatus, tags map[string]string) cfntypes.Stack {
+	stack := cfntypes.Stack{StackName: aws.String(name), StackStatus: status}
+	for key, value := range tags {
+		stack.Tags = append(stack.Tags, cfntypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return stack
+}
+
+func Test_taggedStackNames(t *testing.T) {
+	runTags := map[string]string{"run": "1234"}
+	d := &deployer{
+		UpOptions:   &UpOptions{},
+		clusterName: "test",
+		cfnClient: &fakeCloudFormation{stacks: []cfntypes.Stack{
+			testStack("eksctl-test-cluster", cfntypes.StackStatusDeleteFailed, map[string]string{"alpha.eksctl.io/cluster-name": "test", "run": "1234"}),
+			testStack("eksctl-test-nodegroup-ng-a", cfntypes.StackStatusCreateComplete, map[string]string{"alpha.eksctl.io/cluster-name": "test", "alpha.eksctl.io/nodegroup-name": "ng-a", "run": "1234"}),
+			testStack("eksctl-test-nodegroup-ng-b", cfntypes.StackStatusCreateComplete, map[string]string{"alpha.eksctl.io/cluster-name": "test", "alpha.eksctl.io/nodegroup-name": "ng-b", "run": "1234"}),
+			// deleted, another run's, and another cluster's stacks are left alone
+			testStack("eksctl-test-nodegroup-ng-c", cfntypes.StackStatusDeleteComplete, map[string]string{"alpha.eksctl.io/cluster-name": "test", "alpha.eksctl.io/nodegroup-name": "ng-c", "run": "1234"}),
+			testStack("eksctl-test-nodegroup-ng-d", cfntypes.StackStatusCreateComplete, map[string]string{"alpha.eksctl.io/cluster-name": "test", "alpha.eksctl.io/nodegroup-name": "ng-d", "run": "5678"}),
+			testStack("eksctl-other-cluster", cfntypes.StackStatusCreateComplete, map[string]string{"alpha.eksctl.io/cluster-name": "other", "run": "1234"}),
+			testStack("untagged", cfntypes.StackStatusCreateComplete, nil),
+		}},
+	}
+	stackNames, err := d.taggedStackNames(context.Background(), runTags, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eksctl-test-cluster", "eksctl-test-nodegroup-ng-a", "eksctl-test-nodegroup-ng-b"}, stackNames)
+
+	stackNames, err = d.taggedStackNames(context.Background(), runTags, []string{"ng-b"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eksctl-test-nodegroup-ng-b"}, stackNames)
+
+	stackNames, err = d.taggedStackNames(context.Background(), map[string]string{"run": "1234", "owner": "team-a"}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, stackNames)
+}
@@ -2,10 +2,12 @@ package eksctl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
+	"time"
 
 	"github.com/aws/aws-k8s-tester/internal/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -15,32 +17,159 @@ import (
 )
 
 type UpOptions struct {
-	Region                string   `flag:"region" desc:"AWS region for EKS cluster"`
-	KubernetesVersion     string   `flag:"kubernetes-version" desc:"cluster Kubernetes version"`
-	Nodes                 int      `flag:"nodes" desc:"number of nodes to launch in cluster"`
-	AMI                   string   `flag:"ami" desc:"Node AMI"`
-	InstanceTypes         []string `flag:"instance-types" desc:"Node instance types"`
-	ConfigFile            string   `flag:"config-file" desc:"Path to eksctl config file (if provided, other flags are ignored)"`
-	AvailabilityZones     []string `flag:"availability-zones" desc:"Node availability zones"`
-	AMIFamily             string   `flag:"ami-family" desc:"AMI family to use (AmazonLinux2023, Bottlerocket)"`
-	EFAEnabled            bool     `flag:"efa-enabled" desc:"Enable Elastic Fabric Adapter for the nodegroup"`
-	VolumeSize            int      `flag:"volume-size" desc:"Size of the node root volume in GB"`
-	PrivateNetworking     bool     `flag:"private-networking" desc:"Use private networking for nodes"`
-	WithOIDC              bool     `flag:"with-oidc" desc:"Enable OIDC provider for IAM roles for service accounts"`
-	DeployTarget          string   `flag:"deploy-target" desc:"The target to deploy, supported values: cluster | nodegroup (defaults to 'cluster'). It is a thin wrapper to eksctl create subcommand with limited supported values."`
-	ClusterName           string   `flag:"cluster-name" desc:"Name of the EKS cluster (defaults to RunID if not specified)"`
-	UseUnmanagedNodegroup bool     `flag:"unmanaged-nodegroup" desc:"Use unmanaged nodegroup instead of managed nodegroup"`
-	NodegroupName         string   `flag:"nodegroup-name" desc:"Name of the nodegroup (defaults to 'ng-1')"`
+	Region                 string        `flag:"region" desc:"AWS region for EKS cluster"`
+	KubernetesVersion      string        `flag:"kubernetes-version" desc:"cluster Kubernetes version"`
+	Nodes                  int           `flag:"nodes" desc:"number of nodes to launch in cluster"`
+	AMI                    string        `flag:"ami" desc:"Node AMI"`
+	InstanceTypes          []string      `flag:"instance-types" desc:"Node instance types"`
+	ConfigFile             string        `flag:"config-file" desc:"Path to eksctl config file (if provided, other flags are ignored)"`
+	AvailabilityZones      []string      `flag:"availability-zones" desc:"Node availability zones"`
+	AvailabilityZoneIDs    []string      `flag:"availability-zone-ids" desc:"Node availability zone IDs (e.g. use1-az1), resolved to the account's availability zone names. Cannot be used with --availability-zones"`
+	AMIFamily              string        `flag:"ami-family" desc:"AMI family to use (AmazonLinux2023, Bottlerocket, or a Windows family such as WindowsServer2022FullContainer)"`
+	NodeAMIType            string        `flag:"node-ami-type" desc:"AMI type shorthand, as used by the EKS managed nodegroup API (e.g. AL2023_x86_64_STANDARD, BOTTLEROCKET_ARM_64). Sets --ami-family, and the AMI when eksctl can't choose the variant"`
+	EFAEnabled             bool          `flag:"efa-enabled" desc:"Enable Elastic Fabric Adapter for the nodegroup, in a cluster placement group, and wait for nodes to advertise EFA devices. Requires --instance-types that support EFA in one availability zone"`
+	EFAPlacementGroup      string        `flag:"efa-placement-group" desc:"Existing placement group for the --efa-enabled nodegroup, instead of the cluster placement group eksctl creates"`
+	VolumeSize             int           `flag:"volume-size" desc:"Size of the node root volume in GB"`
+	VolumeType             string        `flag:"volume-type" desc:"Type of the node root volume (gp2, gp3, io1, io2, sc1, st1). Defaults to gp3"`
+	VolumeIOPS             int           `flag:"volume-iops" desc:"Provisioned IOPS of the node root volume, for gp3 (3000-16000), io1 (100-64000), and io2 (100-256000) volumes"`
+	VolumeThroughput       int           `flag:"volume-throughput" desc:"Throughput of the node root volume in MiB/s, for gp3 volumes (125-1000)"`
+	PrivateNetworking      bool          `flag:"private-networking" desc:"Use private networking for nodes"`
+	WithOIDC               bool          `flag:"with-oidc" desc:"Enable OIDC provider for IAM roles for service accounts"`
+	DeployTarget           string        `flag:"deploy-target" desc:"The target to deploy, supported values: cluster | nodegroup (defaults to 'cluster'). It is a thin wrapper to eksctl create subcommand with limited supported values."`
+	ClusterName            string        `flag:"cluster-name" desc:"Name of the EKS cluster (defaults to RunID if not specified)"`
+	ClusterNamePrefix      string        `flag:"cluster-name-prefix" desc:"Prefix added to the cluster name (from --cluster-name or the RunID), separated by '-'"`
+	ClusterNameSuffix      string        `flag:"cluster-name-suffix" desc:"Suffix added to the cluster name (from --cluster-name or the RunID), separated by '-'"`
+	UseUnmanagedNodegroup  bool          `flag:"unmanaged-nodegroup" desc:"Use unmanaged nodegroup instead of managed nodegroup"`
+	NodegroupName          string        `flag:"nodegroup-name" desc:"Name of the nodegroup (defaults to 'ng-1')"`
+	NodeReleaseVersion     string        `flag:"nodegroup-release-version" desc:"EKS-optimized AMI release version to pin the managed nodegroup to, e.g. 1.33.0-20250704, instead of the latest"`
+	EnableFullECRAccess    bool          `flag:"enable-full-ecr-access" desc:"Grant the node role full access to ECR, instead of the default read-only access"`
+	DeleteOnFailure        bool          `flag:"delete-on-failure" desc:"Delete the partially created cluster or nodegroup if eksctl fails to create it"`
+	UpTimeout              time.Duration `flag:"up-timeout" desc:"Overall time limit for Up. Defaults to no limit"`
+	ClusterCreateTimeout   time.Duration `flag:"cluster-create-timeout" desc:"Time limit for the eksctl create command. Defaults to no limit, other than --up-timeout"`
+	WriteKubeconfigTimeout time.Duration `flag:"write-kubeconfig-timeout" desc:"Time limit for writing the kubeconfig. Defaults to no limit, other than --up-timeout"`
+	AdditionalKubeconfigs  []string      `flag:"additional-kubeconfig" desc:"Additional paths to copy the kubeconfig to after it is written. May be repeated"`
+	DeleteTimeout          time.Duration `flag:"delete-timeout" desc:"Time limit for the eksctl delete command in Down. Defaults to no limit"`
+	EksctlVerbosity        int           `flag:"eksctl-verbosity" desc:"eksctl log level (0-5), passed as --verbose to the create and delete commands"`
+	IMDSHopLimit           int           `flag:"imds-hop-limit" desc:"Instance metadata PUT response hop limit for the nodegroup's instances (1-64). Use 2 to allow pods to reach IMDS"`
+	KubeletExtraArgs       string        `flag:"kubelet-extra-args" desc:"Additional kubelet flags for nodes, e.g. '--eviction-hard=memory.available<5%'. Requires --ami with AL2 or AL2023"`
+	InstallGPUDevicePlugin bool          `flag:"install-gpu-device-plugin" desc:"Install the NVIDIA device plugin after the cluster is up and wait for GPUs to be allocatable. Requires --instance-types with NVIDIA GPUs"`
+	AutoGPUDevicePlugin    bool          `flag:"auto-install-gpu-device-plugin" desc:"Install the NVIDIA device plugin as with --install-gpu-device-plugin when all of --instance-types have NVIDIA GPUs (e.g. p5.48xlarge, g6.xlarge), and skip it otherwise"`
+	MinReadyNodes          int           `flag:"min-ready-nodes" desc:"Minimum number of Ready nodes for the cluster to be considered up. Defaults to --nodes"`
+	DesiredCapacity        int           `flag:"desired-capacity" desc:"Initial number of nodes in the nodegroup, for tests that scale it after creation. Must be between the nodegroup's minimum (--min-ready-nodes, or --nodes) and maximum (--nodes). Defaults to --nodes"`
+	VPCCIDR                string        `flag:"vpc-cidr" desc:"IPv4 CIDR of the VPC created by eksctl, between /16 and /24. Defaults to eksctl's choice"`
+	VPCID                  string        `flag:"vpc-id" desc:"Existing VPC to create the cluster in, instead of letting eksctl create one. Requires --private-subnet-ids or --public-subnet-ids"`
+	PrivateSubnetIDs       []string      `flag:"private-subnet-ids" desc:"Existing private subnets of the --vpc-id for the cluster and its nodegroups"`
+	PublicSubnetIDs        []string      `flag:"public-subnet-ids" desc:"Existing public subnets of the --vpc-id for the cluster and its nodegroups"`
+	NodegroupsFile         string        `flag:"nodegroups-file" desc:"Path to a YAML list of nodegroups (name, amiFamily, instanceTypes, nodes, volumeSize) to create instead of the single nodegroup from flags. Options left out of an entry default to the flags"`
+	NodegroupAMIFamilies   []string      `flag:"nodegroup-ami-families" desc:"Create a nodegroup for each of these AMI families (AmazonLinux2, AmazonLinux2023, Bottlerocket, or a Windows family), suffixed with the lower-cased family name, to compare them in one cluster. Cannot be used with --ami-family, --node-ami-type, or --ami"`
+	NodegroupParallelism   int           `flag:"max-nodegroup-create-parallelism" desc:"Maximum number of nodegroups eksctl creates at once, e.g. to stay within CloudFormation's concurrent stack limits with --nodegroup-per-az. Use 1 to create them one at a time. Defaults to eksctl's default (8)"`
+	NodegroupPerAZ         bool          `flag:"nodegroup-per-az" desc:"Create one nodegroup per availability zone, each pinned to its zone and named with the zone as a suffix. Requires --availability-zones or --availability-zone-ids"`
+	NodeSubnetIDs          []string      `flag:"node-subnet-ids" desc:"Existing subnets to launch the nodegroup in, e.g. in a Local Zone or on an Outpost"`
+	LocalZone              string        `flag:"local-zone" desc:"Local Zone to place the nodegroup in. Requires --unmanaged-nodegroup and --node-subnet-ids in that zone"`
+	OutpostARN             string        `flag:"outpost-arn" desc:"ARN of the Outpost to place the nodegroup on. Requires --unmanaged-nodegroup"`
+	NodeNamePrefix         string        `flag:"node-name-prefix" desc:"Prefix of the Name tag of the nodegroup's instances, which are tagged <prefix>-<nodegroup>"`
+	Tags                   []string      `flag:"tags" desc:"Tags (key=value pairs) for the CloudFormation stacks eksctl creates, which CloudFormation propagates to the stacks' resources, and for the nodegroups. Down uses them to find the run's leftover resources. See --nodegroup-tags-file for tags on the nodegroups only"`
+	NodeLabels             []string      `flag:"node-labels" desc:"Kubernetes labels (key=value pairs) for the nodegroup's nodes, verified on the Node objects after the cluster is up"`
+	NodeTaints             []string      `flag:"node-taints" desc:"Kubernetes taints (key[=value]:effect) for the nodegroup's nodes, verified on the Node objects after the cluster is up"`
+	Reserve                []string      `flag:"reserve" desc:"Reserve the nodegroup's nodes with a label and a NoSchedule taint for each key=value pair, so only pods that tolerate the taint run on them"`
+	NodegroupTagsFile      string        `flag:"nodegroup-tags-file" desc:"Path to a YAML map of tags for the nodegroups. Values are templates that may reference {{.ClusterName}} and {{.Region}}"`
+	Profile                string        `flag:"profile" desc:"Name of a node hardware profile in --profiles-file. Options set by flags take precedence over the profile, even when set to their zero value, e.g. --nodes=0 or --efa-enabled=false"`
+	ProfilesFile           string        `flag:"profiles-file" desc:"Path to a YAML file of named node hardware profiles (AMI, instance types, volume size, etc.)"`
+	RenderConfigTo         string        `flag:"render-config-to" desc:"Also write the rendered cluster config to this path before creating the cluster, e.g. for auditing what was deployed"`
+	IPv6                   bool          `flag:"ipv6" desc:"Create an IPv6 cluster. Enables OIDC and creates the VPC CNI, CoreDNS, and kube-proxy as managed addons"`
+	IPFamily               string        `flag:"ip-family" desc:"IP family of the cluster, ipv4 (default) or ipv6. ipv6 is the same as --ipv6"`
+	VPCCNIVersion          string        `flag:"vpc-cni-version" desc:"Version of the VPC CNI addon for --ipv6 (at least 1.10.0) or --prefix-delegation (at least 1.9.0). Defaults to 'latest'"`
+	PrefixDelegation       bool          `flag:"prefix-delegation" desc:"Enable the VPC CNI's prefix delegation, and raise the nodegroup's max pods to match. Requires Nitro instance types"`
+	WarmPrefixTarget       int           `flag:"warm-prefix-target" desc:"WARM_PREFIX_TARGET for the VPC CNI with --prefix-delegation. Defaults to the VPC CNI's default"`
+	ContainerInsights      bool          `flag:"enable-container-insights" desc:"Create the CloudWatch observability addon with an IAM role for the CloudWatch agent, and wait for Container Insights metrics to reach CloudWatch. Requires --with-oidc"`
+	BottlerocketSettings   string        `flag:"bottlerocket-settings-file" desc:"Path to a TOML (.toml) or YAML file of Bottlerocket settings, e.g. kernel sysctls, host containers, and kubelet settings, rendered into the Bottlerocket nodegroups' bottlerocket.settings"`
+	Spot                   bool          `flag:"spot" desc:"Launch the nodegroup's nodes as Spot instances. Unmanaged nodegroups use a capacity-optimized Spot instances distribution across the instance types"`
+	SpotInstanceTypes      []string      `flag:"spot-instance-types" desc:"Instance types for the --spot nodegroup, in place of --instance-types. List several to spread across Spot capacity pools"`
+	FargateProfiles        []string      `flag:"fargate-profiles" desc:"Fargate profiles (name=namespace pairs) to create with the cluster, deleted before the cluster in Down. Repeat a name to select several namespaces, which may use * and ? wildcards"`
+	Addons                 []string      `flag:"addons" desc:"EKS managed addons (name[=version], e.g. vpc-cni=latest,coredns,aws-ebs-csi-driver) to create with the cluster and wait to become ACTIVE. Addons without a version get EKS's default for the cluster"`
+	AddonConfigurationFile string        `flag:"addon-configuration-values-file" desc:"Path to a YAML map of --addons names to their configuration values"`
+	Karpenter              bool          `flag:"karpenter" desc:"Install Karpenter with the cluster, and apply a default NodePool and EC2NodeClass after it is up. Requires --with-oidc"`
+	KarpenterVersion       string        `flag:"karpenter-version" desc:"Version of the Karpenter chart for --karpenter, at least 1.0.0. Defaults to '1.2.1'"`
+	AutoMode               bool          `flag:"auto-mode" desc:"Create an EKS Auto Mode cluster, which launches nodes for pending pods instead of creating nodegroups, and wait for Auto Mode to run a smoke test pod"`
+	AutoModeNodePools      []string      `flag:"auto-mode-node-pools" desc:"Built-in node pools (general-purpose, system) for --auto-mode. Defaults to both"`
+	SSHPublicKey           string        `flag:"ssh-public-key" desc:"Path to an SSH public key file, or the name of an existing EC2 key pair, to allow SSH to the nodes for debugging"`
+	EnableSSM              bool          `flag:"enable-ssm" desc:"Wait for the nodegroup's instances to come online in Systems Manager after the cluster is up, so Session Manager and Run Command can reach them"`
+	EndpointPrivateAccess  bool          `flag:"endpoint-private-access" desc:"Enable the API server's private endpoint, reachable from within the cluster's VPC"`
+	EndpointPublicAccess   bool          `flag:"endpoint-public-access" desc:"Enable the API server's public endpoint. Use --endpoint-public-access=false with --endpoint-private-access for a private-only cluster, which must be created from within its VPC. Defaults to true"`
+	PublicAccessCIDRs      []string      `flag:"public-access-cidrs" desc:"IPv4 CIDRs allowed to reach the API server's public endpoint. Defaults to 0.0.0.0/0"`
+	KMSKeyARN              string        `flag:"kms-key-arn" desc:"ARN of a KMS key to envelope encrypt the cluster's secrets with"`
+	CreateKMSKey           bool          `flag:"create-kms-key" desc:"Create a KMS key to envelope encrypt the cluster's secrets with, and schedule its deletion in Down after the cluster is deleted"`
+	ClusterLogging         []string      `flag:"enable-cluster-logging" desc:"Control plane log types (api, audit, authenticator, controllerManager, scheduler, or all) to send to CloudWatch Logs"`
+	ClusterLogRetention    int           `flag:"cluster-log-retention-days" desc:"Days to keep the --enable-cluster-logging logs for, one of CloudWatch Logs' retention periods. Defaults to keeping them indefinitely"`
+	ConfigPatch            string        `flag:"config-patch" desc:"JSON or YAML snippet deep-merged onto the rendered cluster config (JSON merge patch rules); patched fields override the flag-driven values"`
 }
 
 func (d *deployer) verifyUpFlags() error {
 	supportedDeployTargets := []string{"cluster", "nodegroup"}
+	if d.ConfigFile != "" && d.EnableFullECRAccess {
+		return fmt.Errorf("--enable-full-ecr-access cannot be used with --config-file, set iam.withAddonPolicies.imageBuilder in the config file instead")
+	}
+	if d.ConfigFile != "" && d.ConfigPatch != "" {
+		return fmt.Errorf("--config-patch cannot be used with --config-file, edit the config file instead")
+	}
+	if d.ConfigFile != "" && d.RenderConfigTo != "" {
+		return fmt.Errorf("--render-config-to cannot be used with --config-file, the config file is used as-is")
+	}
+	if d.ConfigFile != "" && (d.ClusterNamePrefix != "" || d.ClusterNameSuffix != "") {
+		return fmt.Errorf("--cluster-name-prefix and --cluster-name-suffix cannot be used with --config-file")
+	}
+	if d.ConfigFile != "" && d.Profile != "" {
+		return fmt.Errorf("--profile cannot be used with --config-file")
+	}
+	if d.ConfigFile != "" && d.ContainerInsights {
+		return fmt.Errorf("--enable-container-insights cannot be used with --config-file, add the amazon-cloudwatch-observability addon to the config file instead")
+	}
+	if d.ConfigFile != "" && len(d.ClusterLogging) > 0 {
+		return fmt.Errorf("--enable-cluster-logging cannot be used with --config-file, set cloudWatch.clusterLogging in the config file instead")
+	}
+	if d.ConfigFile != "" && len(d.Tags) > 0 {
+		return fmt.Errorf("--tags cannot be used with --config-file, set metadata.tags in the config file instead")
+	}
+	if d.ConfigFile != "" && (len(d.NodeLabels) > 0 || len(d.NodeTaints) > 0 || len(d.Reserve) > 0) {
+		return fmt.Errorf("--node-labels, --node-taints, and --reserve cannot be used with --config-file, set labels and taints on the nodegroups in the config file instead")
+	}
+	if d.EksctlVerbosity < 0 || d.EksctlVerbosity > 5 {
+		return fmt.Errorf("--eksctl-verbosity must be between 0 and 5")
+	}
+	if d.NodegroupParallelism < 0 {
+		return fmt.Errorf("--max-nodegroup-create-parallelism must be positive")
+	}
+	if err := d.verifyTimeouts(); err != nil {
+		return err
+	}
+	if err := d.verifyAdditionalKubeconfigs(); err != nil {
+		return err
+	}
+	if d.ConfigPatch != "" {
+		if _, err := parseConfigPatch(d.ConfigPatch); err != nil {
+			return err
+		}
+	}
 	// Skip validation if using a config file
 	if d.ConfigFile != "" {
 		klog.Infof("Using config file %s, skipping command-line flag validation", d.ConfigFile)
 		return nil
 	}
 
+	if d.Profile != "" {
+		if err := d.applyProfile(); err != nil {
+			return err
+		}
+	}
+
+	// --ip-family sets --ipv6, which turns on --with-oidc, so these go before the checks that read either
+	if err := d.verifyIPFamily(); err != nil {
+		return err
+	}
+	if err := d.verifyClusterName(); err != nil {
+		return err
+	}
+
 	if d.KubernetesVersion == "" {
 		klog.Infof("--kubernetes-version is empty, attempting to detect it...")
 		detectedVersion, err := detectKubernetesVersion()
@@ -49,6 +178,11 @@ func (d *deployer) verifyUpFlags() error {
 		}
 		klog.Infof("detected --kubernetes-version=%s", detectedVersion)
 		d.KubernetesVersion = detectedVersion
+	} else if err := d.verifyKubernetesVersion(); err != nil {
+		return err
+	}
+	if err := d.verifyIPv6(); err != nil {
+		return err
 	}
 	if d.Nodes < 0 {
 		return fmt.Errorf("number of nodes must be greater than zero")
@@ -58,9 +192,22 @@ func (d *deployer) verifyUpFlags() error {
 		klog.V(2).Infof("Using default number of nodes: %d", d.Nodes)
 	}
 
-	// Validate instance types for unmanaged nodegroups
+	if d.MinReadyNodes < 0 || d.MinReadyNodes > d.Nodes {
+		return fmt.Errorf("--min-ready-nodes must be between 0 and --nodes (%d)", d.Nodes)
+	}
+	if d.DesiredCapacity < 0 || d.DesiredCapacity > d.Nodes {
+		return fmt.Errorf("--desired-capacity must be between 0 and --nodes (%d)", d.Nodes)
+	}
+	if d.DesiredCapacity > 0 && d.DesiredCapacity < *d.minSize() {
+		return fmt.Errorf("--desired-capacity (%d) must not be less than the nodegroup's minimum size (%d), set --min-ready-nodes to lower it", d.DesiredCapacity, *d.minSize())
+	}
+
+	if err := d.verifySpot(); err != nil {
+		return err
+	}
+	// Validate instance types for unmanaged nodegroups, which only use several for a Spot instances distribution
 	if d.UseUnmanagedNodegroup {
-		if len(d.InstanceTypes) > 1 {
+		if len(d.InstanceTypes) > 1 && !d.Spot {
 			return fmt.Errorf("Unmanaged nodegroups only support a single instance type. Using the first one: %s", d.InstanceTypes[0])
 		} else if len(d.InstanceTypes) == 0 {
 			// If no instance type specified, use a default
@@ -72,9 +219,138 @@ func (d *deployer) verifyUpFlags() error {
 	if d.DeployTarget != "" && !slices.Contains(supportedDeployTargets, d.DeployTarget) {
 		return fmt.Errorf("Unsupported deploy target: %s, supported options: `cluster`, `nodegroup`.", d.DeployTarget)
 	} else if d.DeployTarget == "" {
-			// If no deploy target specified, use "cluster" as default
-			d.DeployTarget = "cluster"
-			klog.Infof("No deploy target specified. Using default: %s", d.DeployTarget)
+		// If no deploy target specified, use "cluster" as default
+		d.DeployTarget = "cluster"
+		klog.Infof("No deploy target specified. Using default: %s", d.DeployTarget)
+	}
+
+	if err := d.verifyContainerInsights(); err != nil {
+		return err
+	}
+	if err := d.verifyFargateProfiles(); err != nil {
+		return err
+	}
+	if err := d.verifyAddons(); err != nil {
+		return err
+	}
+	if err := d.verifyKarpenter(); err != nil {
+		return err
+	}
+	if err := d.verifyAutoMode(); err != nil {
+		return err
+	}
+
+	if err := d.verifyIMDSHopLimit(); err != nil {
+		return err
+	}
+
+	if err := d.detectNodeArchitecture(); err != nil {
+		return err
+	}
+
+	if err := d.verifyNodegroupAMIFamilies(); err != nil {
+		return err
+	}
+	if d.NodegroupsFile != "" {
+		if err := d.loadNodegroupsFile(); err != nil {
+			return err
+		}
+	}
+	if d.NodeAMIType != "" {
+		if err := d.applyNodeAMIType(); err != nil {
+			return err
+		}
+	}
+	if err := d.verifyAMI(); err != nil {
+		return err
+	}
+
+	if err := d.verifyNodeNamePrefix(); err != nil {
+		return err
+	}
+	if _, err := parseStackTags(d.Tags); err != nil {
+		return err
+	}
+	if err := d.verifyNodeLabelsAndTaints(); err != nil {
+		return err
+	}
+	if d.NodegroupTagsFile != "" {
+		if err := d.loadNodegroupTags(); err != nil {
+			return err
+		}
+	}
+	if d.BottlerocketSettings != "" {
+		if err := d.loadBottlerocketSettings(); err != nil {
+			return err
+		}
+	}
+
+	if err := d.verifyVPCCIDR(); err != nil {
+		return err
+	}
+	if err := d.verifyExistingVPC(); err != nil {
+		return err
+	}
+	if err := d.verifyPrefixDelegation(); err != nil {
+		return err
+	}
+	if err := d.verifyNodeReleaseVersion(); err != nil {
+		return err
+	}
+	if err := d.verifyVolumeOptions(); err != nil {
+		return err
+	}
+	if err := d.verifyWindowsNodegroups(); err != nil {
+		return err
+	}
+
+	if err := d.verifyKubeletExtraArgs(); err != nil {
+		return err
+	}
+	if err := d.describeExistingCluster(); err != nil {
+		return err
+	}
+
+	if d.InstallGPUDevicePlugin {
+		if err := d.verifyGPUInstanceTypes(); err != nil {
+			return err
+		}
+	} else if d.AutoGPUDevicePlugin {
+		if err := d.detectGPUDevicePlugin(); err != nil {
+			return err
+		}
+	}
+
+	if err := d.resolveAvailabilityZones(); err != nil {
+		return err
+	}
+	if d.NodegroupPerAZ && len(d.AvailabilityZones) == 0 {
+		return fmt.Errorf("--nodegroup-per-az requires --availability-zones or --availability-zone-ids")
+	}
+	if err := d.verifyEdgePlacement(); err != nil {
+		return err
+	}
+	if err := d.verifyEFA(); err != nil {
+		return err
+	}
+	if err := d.verifySSHPublicKey(); err != nil {
+		return err
+	}
+	if err := d.verifyEndpointAccess(); err != nil {
+		return err
+	}
+	if err := d.verifySecretsEncryption(); err != nil {
+		return err
+	}
+	if err := d.verifyClusterLogging(); err != nil {
+		return err
+	}
+	if err := d.verifyPrivateEgress(); err != nil {
+		return err
+	}
+
+	if err := d.verifyInstanceTypeOfferings(); err != nil {
+		return err
 	}
 
 	return nil
@@ -87,18 +363,28 @@ func (d *deployer) Up() error {
 		return fmt.Errorf("up flags are invalid: %v", err)
 	}
 
-	if d.UseUnmanagedNodegroup {
+	if d.AutoMode {
+		klog.Infof("Using EKS Auto Mode for cluster %s", d.clusterName)
+	} else if d.UseUnmanagedNodegroup {
 		klog.Infof("Using unmanaged nodegroup for cluster %s", d.clusterName)
 	} else {
 		klog.Infof("Using managed nodegroup for cluster %s", d.clusterName)
 	}
 
+	ctx, cancel := contextWithOptionalTimeout(context.Background(), d.UpTimeout)
+	defer cancel()
+
 	var args []string
 
 	if d.ConfigFile != "" {
 		// If config file is provided, use it
 		args = d.renderEksctlArgs(d.ConfigFile)
 	} else {
+		if d.CreateKMSKey {
+			if err := d.createKMSKey(ctx); err != nil {
+				return err
+			}
+		}
 		// Use rendered cluster config
 		clusterConfig, err := d.RenderClusterConfig()
 		if err != nil {
@@ -106,6 +392,12 @@ func (d *deployer) Up() error {
 		}
 		klog.Infof("Rendered cluster config: %s", string(clusterConfig))
 
+		if d.RenderConfigTo != "" {
+			if err := writeRenderedConfig(d.RenderConfigTo, clusterConfig); err != nil {
+				return err
+			}
+		}
+
 		clusterConfigFile, err := os.CreateTemp("", "kubetest2-eksctl-cluster-config")
 		if err != nil {
 			return err
@@ -120,9 +412,18 @@ func (d *deployer) Up() error {
 		args = d.renderEksctlArgs(clusterConfigFile.Name())
 	}
 
-	err := util.ExecuteCommand("eksctl", args...)
+	createCtx, cancelCreate := contextWithOptionalTimeout(ctx, d.ClusterCreateTimeout)
+	defer cancelCreate()
+	err := util.ExecuteCommandContext(createCtx, "eksctl", args...)
 	if err != nil {
-		return fmt.Errorf("failed to create cluster: %v", err)
+		err = fmt.Errorf("failed to create cluster: %v", err)
+		if d.DeleteOnFailure {
+			klog.Warningf("%v, deleting partially created resources...", err)
+			if deleteErr := d.Down(); deleteErr != nil {
+				return errors.Join(err, fmt.Errorf("failed to clean up after failure: %v", deleteErr))
+			}
+		}
+		return err
 	}
 
 	// Write kubeconfig to the rundir
@@ -146,22 +447,142 @@ func (d *deployer) Up() error {
 		"--kubeconfig", kubeConfigPath,
 	}
 
-	err = util.ExecuteCommand("eksctl", writeKubeconfigArgs...)
+	writeKubeconfigCtx, cancelWriteKubeconfig := contextWithOptionalTimeout(ctx, d.WriteKubeconfigTimeout)
+	defer cancelWriteKubeconfig()
+	err = util.ExecuteCommandContext(writeKubeconfigCtx, "eksctl", writeKubeconfigArgs...)
 	if err != nil {
 		return fmt.Errorf("failed to write kubeconfig: %v", err)
 	}
 
 	klog.Infof("Successfully wrote kubeconfig to %s", kubeConfigPath)
 	d.KubeconfigPath = kubeConfigPath
+
+	if err := d.copyKubeconfig(kubeConfigPath); err != nil {
+		return err
+	}
+
+	if err := d.writeClusterMetadata(); err != nil {
+		return err
+	}
+
+	if err := d.checkEndpointAccess(ctx); err != nil {
+		return err
+	}
+	if err := d.checkClusterLogging(ctx); err != nil {
+		return err
+	}
+
+	if err := d.applyIMDSHopLimit(); err != nil {
+		return err
+	}
+
+	if d.EnableSSM {
+		if err := d.waitForSSMRegistration(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := d.checkNodeLabelsAndTaints(); err != nil {
+		return err
+	}
+
+	if d.InstallGPUDevicePlugin {
+		if err := d.installGPUDevicePlugin(ctx); err != nil {
+			return err
+		}
+	}
+
+	if d.EFAEnabled {
+		if err := d.waitForEFAAllocatable(ctx); err != nil {
+			return err
+		}
+	}
+
+	if d.Karpenter {
+		if err := d.applyKarpenterNodePool(ctx); err != nil {
+			return err
+		}
+	}
+
+	if d.AutoMode {
+		if err := d.waitForAutoModeSmokeTest(ctx); err != nil {
+			return err
+		}
+	}
+
+	if len(d.Addons) > 0 {
+		if err := d.waitForAddonsActive(ctx); err != nil {
+			return err
+		}
+	}
+
+	if d.ContainerInsights {
+		if err := d.waitForContainerInsightsMetrics(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRenderedConfig writes the rendered cluster config to path, creating its directory if needed
+func writeRenderedConfig(path string, clusterConfig []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for --render-config-to: %v", err)
+	}
+	if err := os.WriteFile(path, clusterConfig, 0644); err != nil {
+		return fmt.Errorf("failed to write rendered cluster config to %s: %v", path, err)
+	}
+	klog.Infof("Wrote rendered cluster config to %s", path)
 	return nil
 }
 
+// verifyTimeouts checks that the subcommand timeouts fit within --up-timeout
+func (d *deployer) verifyTimeouts() error {
+	timeouts := []struct {
+		flag    string
+		timeout time.Duration
+	}{
+		{"--up-timeout", d.UpTimeout},
+		{"--cluster-create-timeout", d.ClusterCreateTimeout},
+		{"--write-kubeconfig-timeout", d.WriteKubeconfigTimeout},
+	}
+	for _, t := range timeouts {
+		if t.timeout < 0 {
+			return fmt.Errorf("%s must be positive", t.flag)
+		}
+		if d.UpTimeout > 0 && t.timeout > d.UpTimeout {
+			return fmt.Errorf("%s (%v) must not exceed --up-timeout (%v)", t.flag, t.timeout, d.UpTimeout)
+		}
+	}
+	return nil
+}
+
+// contextWithOptionalTimeout returns a context with the timeout applied, unless the timeout is zero
+func contextWithOptionalTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
 func (d *deployer) renderEksctlArgs(configFilePath string) []string {
-	return []string{
+	args := []string{
 		"create",
 		d.DeployTarget,
 		"--config-file", configFilePath,
+		d.verboseArg(),
+	}
+	if d.NodegroupParallelism > 0 {
+		args = append(args, fmt.Sprintf("--nodegroup-parallelism=%d", d.NodegroupParallelism))
 	}
+	return args
+}
+
+// defaultEksctlVerbosity is eksctl's own default log level
+const defaultEksctlVerbosity = 3
+
+func (d *deployer) verboseArg() string {
+	return fmt.Sprintf("--verbose=%d", d.EksctlVerbosity)
 }
 
 func (d *deployer) IsUp() (up bool, err error) {
@@ -175,6 +596,9 @@ func (d *deployer) IsUp() (up bool, err error) {
 	}
 	switch result.Cluster.Status {
 	case ekstypes.ClusterStatusActive:
+		if d.MinReadyNodes > 0 && d.KubeconfigPath != "" {
+			return d.hasMinReadyNodes()
+		}
 		return true, nil
 	case ekstypes.ClusterStatusCreating:
 		return false, nil
@@ -183,6 +607,20 @@ func (d *deployer) IsUp() (up bool, err error) {
 	}
 }
 
+// hasMinReadyNodes checks that at least --min-ready-nodes nodes are Ready
+func (d *deployer) hasMinReadyNodes() (bool, error) {
+	clientset, err := d.kubernetesClient()
+	if err != nil {
+		return false, err
+	}
+	ready, err := countReadyNodes(context.TODO(), clientset)
+	if err != nil {
+		return false, err
+	}
+	klog.Infof("%d nodes are Ready, --min-ready-nodes is %d", ready, d.MinReadyNodes)
+	return ready >= d.MinReadyNodes, nil
+}
+
 func detectKubernetesVersion() (string, error) {
 	detectedVersion, err := util.DetectKubernetesVersion()
 	if err != nil {
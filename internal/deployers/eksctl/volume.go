@@ -0,0 +1,64 @@
+package eksctl
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// volumeIOPSRanges are the IOPS EBS allows for each root volume type that accepts --volume-iops
+var volumeIOPSRanges = map[string][2]int{
+	eksctl_api.NodeVolumeTypeGP3: {eksctl_api.MinGP3Iops, eksctl_api.MaxGP3Iops},
+	eksctl_api.NodeVolumeTypeIO1: {eksctl_api.MinIO1Iops, eksctl_api.MaxIO1Iops},
+	eksctl_api.NodeVolumeTypeIO2: {eksctl_api.MinIO2Iops, eksctl_api.MaxIO2Iops},
+}
+
+// verifyVolumeOptions checks --volume-iops and --volume-throughput against the limits of the --volume-type,
+// which CloudFormation would otherwise only reject after the cluster is created
+func (d *deployer) verifyVolumeOptions() error {
+	volumeType := d.volumeType()
+	if !slices.Contains(eksctl_api.SupportedNodeVolumeTypes(), volumeType) {
+		return fmt.Errorf("--volume-type must be one of %v", eksctl_api.SupportedNodeVolumeTypes())
+	}
+	if d.VolumeIOPS != 0 {
+		iopsRange, ok := volumeIOPSRanges[volumeType]
+		if !ok {
+			return fmt.Errorf("--volume-iops is not supported for %s volumes, only for %s, %s, and %s", volumeType, eksctl_api.NodeVolumeTypeGP3, eksctl_api.NodeVolumeTypeIO1, eksctl_api.NodeVolumeTypeIO2)
+		}
+		if d.VolumeIOPS < iopsRange[0] || d.VolumeIOPS > iopsRange[1] {
+			return fmt.Errorf("--volume-iops for %s volumes must be between %d and %d: %d", volumeType, iopsRange[0], iopsRange[1], d.VolumeIOPS)
+		}
+	}
+	if d.VolumeThroughput != 0 {
+		if volumeType != eksctl_api.NodeVolumeTypeGP3 {
+			return fmt.Errorf("--volume-throughput is not supported for %s volumes, only for %s", volumeType, eksctl_api.NodeVolumeTypeGP3)
+		}
+		if d.VolumeThroughput < eksctl_api.MinThroughput || d.VolumeThroughput > eksctl_api.MaxThroughput {
+			return fmt.Errorf("--volume-throughput for %s volumes must be between %d and %d MiB/s: %d", volumeType, eksctl_api.MinThroughput, eksctl_api.MaxThroughput, d.VolumeThroughput)
+		}
+	}
+	return nil
+}
+
+// volumeType returns the --volume-type, or eksctl's default if it's not set
+func (d *deployer) volumeType() string {
+	if d.VolumeType == "" {
+		return eksctl_api.DefaultNodeVolumeType
+	}
+	return d.VolumeType
+}
+
+// setVolumeOptions renders --volume-type, --volume-iops, and --volume-throughput onto the nodegroup, leaving eksctl's defaults for those not set
+func (d *deployer) setVolumeOptions(ng *eksctl_api.NodeGroupBase) {
+	if d.VolumeType != "" {
+		ng.VolumeType = aws.String(d.VolumeType)
+	}
+	if d.VolumeIOPS != 0 {
+		ng.VolumeIOPS = aws.Int(d.VolumeIOPS)
+	}
+	if d.VolumeThroughput != 0 {
+		ng.VolumeThroughput = aws.Int(d.VolumeThroughput)
+	}
+}
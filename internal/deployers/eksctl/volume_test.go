@@ -0,0 +1,76 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyVolumeOptions(t *testing.T) {
+	cases := []struct {
+		name             string
+		volumeType       string
+		volumeIOPS       int
+		volumeThroughput int
+		wantErr          string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:             "gp3 by default",
+			volumeIOPS:       6000,
+			volumeThroughput: 500,
+		},
+		{
+			name:       "gp3 iops too low",
+			volumeType: "gp3",
+			volumeIOPS: 125,
+			wantErr:    "--volume-iops for gp3 volumes must be between 3000 and 16000: 125",
+		},
+		{
+			name:             "gp3 throughput too high",
+			volumeThroughput: 2000,
+			wantErr:          "--volume-throughput for gp3 volumes must be between 125 and 1000 MiB/s: 2000",
+		},
+		{
+			name:       "io2 iops",
+			volumeType: "io2",
+			volumeIOPS: 100000,
+		},
+		{
+			name:       "io1 iops too high",
+			volumeType: "io1",
+			volumeIOPS: 100000,
+			wantErr:    "--volume-iops for io1 volumes must be between 100 and 64000: 100000",
+		},
+		{
+			name:       "gp2 iops",
+			volumeType: "gp2",
+			volumeIOPS: 3000,
+			wantErr:    "--volume-iops is not supported for gp2 volumes",
+		},
+		{
+			name:             "io2 throughput",
+			volumeType:       "io2",
+			volumeThroughput: 500,
+			wantErr:          "--volume-throughput is not supported for io2 volumes",
+		},
+		{
+			name:       "unknown type",
+			volumeType: "gp4",
+			wantErr:    "--volume-type must be one of",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := &deployer{UpOptions: &UpOptions{VolumeType: c.volumeType, VolumeIOPS: c.volumeIOPS, VolumeThroughput: c.volumeThroughput}}
+			err := d.verifyVolumeOptions()
+			if c.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package eksctl
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/klog"
+)
+
+const (
+	// eksctl carves public and private subnets in each availability zone out of the VPC CIDR,
+	// so anything smaller than a /24 leaves too little room for nodes and pods
+	minVPCCIDRPrefixLength = 16
+	maxVPCCIDRPrefixLength = 24
+)
+
+var (
+	// minIPv6KubernetesVersion and minIPv6VPCCNIVersion are the oldest versions eksctl accepts for IPv6 clusters
+	minIPv6KubernetesVersion = version.MustParseGeneric("1.21")
+	minIPv6VPCCNIVersion     = version.MustParseGeneric("1.10.0")
+)
+
+// verifyVPCCIDR checks that --vpc-cidr is an IPv4 network of a size eksctl can split into subnets
+func (d *deployer) verifyVPCCIDR() error {
+	if d.VPCCIDR == "" {
+		return nil
+	}
+	ip, network, err := net.ParseCIDR(d.VPCCIDR)
+	if err != nil {
+		return fmt.Errorf("--vpc-cidr is not a valid CIDR: %v", err)
+	}
+	if ip.To4() == nil {
+		return fmt.Errorf("--vpc-cidr must be an IPv4 CIDR: %s", d.VPCCIDR)
+	}
+	if !ip.Equal(network.IP) {
+		return fmt.Errorf("--vpc-cidr must be a network address, did you mean %s?", network)
+	}
+	prefixLength, _ := network.Mask.Size()
+	if prefixLength < minVPCCIDRPrefixLength || prefixLength > maxVPCCIDRPrefixLength {
+		return fmt.Errorf("--vpc-cidr prefix length must be between /%d and /%d: %s", minVPCCIDRPrefixLength, maxVPCCIDRPrefixLength, d.VPCCIDR)
+	}
+	return nil
+}
+
+// verifyIPFamily maps --ip-family onto --ipv6, which the rest of the deployer keys off
+func (d *deployer) verifyIPFamily() error {
+	switch strings.ToLower(d.IPFamily) {
+	case "":
+	case strings.ToLower(eksctl_api.IPV4Family):
+		if d.IPv6 {
+			return fmt.Errorf("--ip-family %s cannot be used with --ipv6", d.IPFamily)
+		}
+	case strings.ToLower(eksctl_api.IPV6Family):
+		d.IPv6 = true
+	default:
+		return fmt.Errorf("--ip-family must be ipv4 or ipv6: %s", d.IPFamily)
+	}
+	return nil
+}
+
+// verifyIPv6 checks the flags that eksctl's IPv6 support depends on.
+// The core addons and OIDC that IPv6 also requires are rendered by CreateClusterConfig.
+func (d *deployer) verifyIPv6() error {
+	if !d.IPv6 {
+		if d.VPCCNIVersion != "" && !d.PrefixDelegation {
+			return fmt.Errorf("--vpc-cni-version requires --ipv6 or --prefix-delegation")
+		}
+		return nil
+	}
+	if d.OutpostARN != "" {
+		return fmt.Errorf("--ipv6 is not supported with --outpost-arn")
+	}
+	if d.KubernetesVersion != "" {
+		kubernetesVersion, err := version.ParseGeneric(d.KubernetesVersion)
+		if err != nil {
+			return fmt.Errorf("--kubernetes-version is invalid: %v", err)
+		}
+		if !kubernetesVersion.AtLeast(minIPv6KubernetesVersion) {
+			return fmt.Errorf("--ipv6 requires --kubernetes-version %s or later", minIPv6KubernetesVersion)
+		}
+	}
+	if d.VPCCNIVersion != "" && d.VPCCNIVersion != "latest" {
+		vpcCNIVersion, err := version.ParseGeneric(d.VPCCNIVersion)
+		if err != nil {
+			return fmt.Errorf("--vpc-cni-version is invalid: %v", err)
+		}
+		if !vpcCNIVersion.AtLeast(minIPv6VPCCNIVersion) {
+			return fmt.Errorf("--ipv6 requires --vpc-cni-version %s or later", minIPv6VPCCNIVersion)
+		}
+	}
+	if !d.WithOIDC {
+		klog.Infof("Enabling OIDC because --ipv6 requires it for the VPC CNI's IAM role")
+		d.WithOIDC = true
+	}
+	if d.AMI != "" {
+		klog.Warningf("--ipv6 is set, make sure --ami %s supports IPv6 networking", d.AMI)
+	}
+	return nil
+}
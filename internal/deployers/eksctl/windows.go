@@ -0,0 +1,71 @@
+package eksctl
+
+import (
+	"fmt"
+
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// windowsMinVolumeSize is the size in GB of the EKS-optimized Windows AMIs' root volume snapshot, which the node's root volume can't be smaller than
+const windowsMinVolumeSize = 50
+
+// windowsAMIFamilies are the Windows AMI families eksctl supports
+var windowsAMIFamilies = []string{
+	eksctl_api.NodeImageFamilyWindowsServer2019CoreContainer,
+	eksctl_api.NodeImageFamilyWindowsServer2019FullContainer,
+	eksctl_api.NodeImageFamilyWindowsServer2022CoreContainer,
+	eksctl_api.NodeImageFamilyWindowsServer2022FullContainer,
+}
+
+// verifyWindowsNodegroups checks that the options for Windows nodegroups are ones Windows nodes support.
+// A new cluster also needs a Linux nodegroup, since CoreDNS and the other system pods only run on Linux.
+func (d *deployer) verifyWindowsNodegroups() error {
+	var windowsFamily string
+	var linux bool
+	for _, placement := range d.nodegroupPlacements() {
+		if !eksctl_api.IsWindowsImage(placement.amiFamily) {
+			linux = true
+			continue
+		}
+		windowsFamily = placement.amiFamily
+		volumeSize := placement.volumeSize
+		if volumeSize == 0 {
+			volumeSize = d.VolumeSize
+		}
+		if volumeSize > 0 && volumeSize < windowsMinVolumeSize {
+			return fmt.Errorf("nodegroup %s uses %s, which needs a root volume of at least %d GB: %d", placement.name, placement.amiFamily, windowsMinVolumeSize, volumeSize)
+		}
+	}
+	if windowsFamily == "" {
+		return nil
+	}
+	if d.DeployTarget == "cluster" && !linux {
+		return fmt.Errorf("a cluster with Windows nodegroups also needs a Linux nodegroup to run CoreDNS, e.g. --nodegroup-ami-families %s,%s",
+			eksctl_api.NodeImageFamilyAmazonLinux2023, windowsFamily)
+	}
+	if d.nodeArchitecture == architectureARM64 {
+		return fmt.Errorf("Windows nodegroups only support %s instance types, but instance types %v are %s", architectureX86_64, d.InstanceTypes, d.nodeArchitecture)
+	}
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--ipv6", d.IPv6},
+		{"--prefix-delegation", d.PrefixDelegation},
+		{"--kubelet-extra-args", d.KubeletExtraArgs != ""},
+	}
+	for _, conflict := range conflicts {
+		if conflict.set {
+			return fmt.Errorf("%s is not supported with Windows nodegroups", conflict.flag)
+		}
+	}
+	return nil
+}
+
+// setWindowsOptions renders the fields a Windows nodegroup needs that differ from the Linux defaults
+func setWindowsOptions(ng *eksctl_api.NodeGroupBase) {
+	// an unset --volume-size renders as 0, which is smaller than the Windows AMI's snapshot, so leave eksctl's default
+	if ng.VolumeSize != nil && *ng.VolumeSize == 0 {
+		ng.VolumeSize = nil
+	}
+}
@@ -0,0 +1,56 @@
+package eksctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	eksctl_api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+func Test_verifyWindowsNodegroups(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{
+		DeployTarget:         "cluster",
+		NodegroupAMIFamilies: []string{"AmazonLinux2023", "WindowsServer2022FullContainer"},
+	}}
+	assert.NoError(t, d.verifyWindowsNodegroups())
+
+	d.VolumeSize = 30
+	assert.ErrorContains(t, d.verifyWindowsNodegroups(), "needs a root volume of at least 50 GB")
+
+	d.VolumeSize = 0
+	d.PrefixDelegation = true
+	assert.ErrorContains(t, d.verifyWindowsNodegroups(), "--prefix-delegation is not supported with Windows nodegroups")
+
+	d = &deployer{UpOptions: &UpOptions{DeployTarget: "cluster", AMIFamily: "WindowsServer2022FullContainer"}}
+	assert.ErrorContains(t, d.verifyWindowsNodegroups(), "also needs a Linux nodegroup")
+
+	d.DeployTarget = "nodegroup"
+	assert.NoError(t, d.verifyWindowsNodegroups())
+
+	d.nodeArchitecture = architectureARM64
+	assert.ErrorContains(t, d.verifyWindowsNodegroups(), "only support x86_64 instance types")
+}
+
+func Test_CreateClusterConfig_windows(t *testing.T) {
+	d := &deployer{UpOptions: &UpOptions{
+		ClusterName:           "test",
+		AMI:                   "ami-0123456789abcdef0",
+		AMIFamily:             "WindowsServer2022FullContainer",
+		UseUnmanagedNodegroup: true,
+	}}
+	cfg, err := d.CreateClusterConfig()
+	assert.NoError(t, err)
+	ng := cfg.NodeGroups[0]
+	assert.Equal(t, "WindowsServer2022FullContainer", ng.AMIFamily)
+	assert.Nil(t, ng.OverrideBootstrapCommand)
+	assert.Nil(t, ng.VolumeSize)
+	assert.Equal(t, eksctl_api.ContainerRuntimeContainerD, *ng.ContainerRuntime)
+
+	d.UseUnmanagedNodegroup = false
+	d.VolumeSize = 100
+	cfg, err = d.CreateClusterConfig()
+	assert.NoError(t, err)
+	mng := cfg.ManagedNodeGroups[0]
+	assert.Nil(t, mng.OverrideBootstrapCommand)
+	assert.Equal(t, 100, *mng.VolumeSize)
+}
@@ -1,12 +1,18 @@
 package util
 
 import (
+	"context"
 	"os"
 	"os/exec"
 )
 
 func ExecuteCommand(name string, args ...string) error {
-	command := exec.Command(name, args...)
+	return ExecuteCommandContext(context.Background(), name, args...)
+}
+
+// ExecuteCommandContext is like ExecuteCommand, but the command is killed if the context is done before it exits.
+func ExecuteCommandContext(ctx context.Context, name string, args ...string) error {
+	command := exec.CommandContext(ctx, name, args...)
 	command.Stdout = os.Stdout
 	command.Stderr = os.Stderr
 	return command.Run()
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxPollInterval caps the exponential backoff of PollUntil
+const maxPollInterval = 2 * time.Minute
+
+// PollUntil calls condition until it returns true or an error, or the timeout expires.
+// The first call is immediate, and the wait between calls doubles from interval up to maxPollInterval.
+// A transient failure should be reported by returning false and a nil error, so that the condition is retried.
+func PollUntil(ctx context.Context, interval time.Duration, timeout time.Duration, condition func(context.Context) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		done, err := condition(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %v: %w", timeout, ctx.Err())
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxPollInterval)
+	}
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_PollUntil(t *testing.T) {
+	calls := 0
+	err := PollUntil(context.Background(), time.Millisecond, time.Second, func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected success after 3 calls, got %d calls and error: %v", calls, err)
+	}
+
+	conditionErr := errors.New("condition failed")
+	err = PollUntil(context.Background(), time.Millisecond, time.Second, func(ctx context.Context) (bool, error) {
+		return false, conditionErr
+	})
+	if !errors.Is(err, conditionErr) {
+		t.Errorf("expected the condition's error, got: %v", err)
+	}
+
+	err = PollUntil(context.Background(), time.Millisecond, 10*time.Millisecond, func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timeout, got: %v", err)
+	}
+}